@@ -3,10 +3,12 @@ package applemaps
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 )
 
@@ -19,6 +21,8 @@ const (
 	searchAutocompleteEndpoint = "searchAutocomplete"
 	etasEndpoint               = "etas"
 	directionsEndpoint         = "directions"
+	placeEndpoint              = "place"
+	alternateIDsEndpoint       = "place/alternateIds"
 	defaultOffset              = 10 * time.Second
 )
 
@@ -34,19 +38,50 @@ type (
 
 		Search(ctx context.Context, query string, opts ...RequestOption) (*SearchResponse, error)
 		SearchAutocomplete(ctx context.Context, query string, opts ...RequestOption) (*SearchAutocompleteResult, error)
+		ResolveCompletion(ctx context.Context, result AutocompleteResult) (*SearchResponse, error)
 
 		Directions(ctx context.Context, origin, destination string, opts ...RequestOption) (*DirectionsResponse, error)
+		DirectionsBetween(ctx context.Context, origin, destination Waypoint, opts ...RequestOption) (*DirectionsResponse, error)
 		Etas(ctx context.Context, origin Location, destinations []Location, opts ...RequestOption) (*EtaResponse, error)
 
+		Place(ctx context.Context, id string, opts ...RequestOption) (*Place, error)
+		Places(ctx context.Context, ids []string, opts ...RequestOption) (*PlacesResponse, error)
+		PlaceAlternateIDs(ctx context.Context, ids []string, opts ...RequestOption) (*AlternateIDsResponse, error)
+
 		SetAuthToken(authToken string)
+		Usage() Usage
 	}
 
 	client struct {
-		client      *http.Client
+		client *http.Client
+
+		// mu guards the token state below.
+		mu          sync.Mutex
 		accessToken AccessToken
 		authToken   string
+		tokenSource TokenSource
+		generation  uint64
 		nextRenewal time.Time
+		renewal     *tokenRenewal
+
 		baseURL     string
+		retryPolicy RetryPolicy
+		limiter     *limiter
+		middleware  []Middleware
+		logger      Logger
+		verbose     bool
+		metrics     Metrics
+
+		maxResponseSize int64
+	}
+
+	// tokenRenewal is an in-flight access token exchange shared by all callers waiting for a new token.
+	// The generation is the one of the auth token that is exchanged.
+	tokenRenewal struct {
+		done       chan struct{}
+		generation uint64
+		token      string
+		err        error
 	}
 )
 
@@ -66,6 +101,23 @@ func NewAppleMaps(httpClient *http.Client, authToken string, options ...ClientOp
 	return mapsClient
 }
 
+// NewAppleMapsWithTokenSource returns a new Apple Maps Server API Client
+// given a http client and a TokenSource providing the JWT Auth Tokens for the API.
+// The token source is asked for an auth token whenever a new access token is requested,
+// so a token source that renews its tokens before they expire keeps the client authorized indefinitely.
+func NewAppleMapsWithTokenSource(httpClient *http.Client, source TokenSource, options ...ClientOption) Client {
+	mapsClient := &client{
+		client:      httpClient,
+		baseURL:     apiBase,
+		tokenSource: source,
+		nextRenewal: time.Now(),
+	}
+	for _, o := range options {
+		o(mapsClient)
+	}
+	return mapsClient
+}
+
 type ClientOption func(c *client)
 
 // WithCustomURL returns a functional ClientOption used to set a custom base URL
@@ -78,79 +130,196 @@ func WithCustomURL(baseURL string) ClientOption {
 
 // getAccessToken either returns the current access token, or requests a new one if needed.
 // A new token will be requested and returned only if the current token expires within the next 10 seconds or is already expired,
-// or if no access token exists yet.
-func (c *client) getAccessToken() (string, error) {
-	if time.Now().After(c.nextRenewal.Add(-defaultOffset)) {
-		reader, err := c.doRequest(context.Background(), c.authToken, tokenEndpoint, nil)
-		if err != nil {
-			return "", err
+// or if no access token exists yet. Concurrent callers share a single in-flight token exchange.
+func (c *client) getAccessToken(ctx context.Context) (string, error) {
+	for {
+		c.mu.Lock()
+		if !time.Now().After(c.nextRenewal.Add(-defaultOffset)) {
+			token := c.accessToken.Token
+			c.mu.Unlock()
+			return token, nil
+		}
+		renewal, leader := c.renewal, false
+		if renewal == nil {
+			renewal, leader = &tokenRenewal{done: make(chan struct{}), generation: c.generation}, true
+			c.renewal = renewal
+		}
+		source := c.authTokenSource()
+		c.mu.Unlock()
+
+		if leader {
+			c.renewAccessToken(ctx, renewal, source)
+		}
+		select {
+		case <-renewal.done:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+
+		// the auth token was replaced using SetAuthToken() during the exchange, so exchange the new one
+		c.mu.Lock()
+		replaced := renewal.generation != c.generation
+		c.mu.Unlock()
+		if replaced {
+			continue
 		}
-		var accessToken AccessToken
-		if err := json.NewDecoder(reader).Decode(&accessToken); err != nil {
-			return "", err
+		// the exchange was started by another caller whose context is done, so try again with our own
+		if !leader && (errors.Is(renewal.err, context.Canceled) || errors.Is(renewal.err, context.DeadlineExceeded)) {
+			continue
 		}
+		return renewal.token, renewal.err
+	}
+}
+
+// renewAccessToken exchanges an auth token from the given source for a new access token, stores it and completes the renewal.
+// The new token is not stored if the auth token was replaced using SetAuthToken() in the meantime.
+func (c *client) renewAccessToken(ctx context.Context, renewal *tokenRenewal, source TokenSource) {
+	var accessToken AccessToken
+	authToken, err := source.Token(ctx)
+	if err == nil {
+		var reader io.Reader
+		reader, err = c.doRequest(ctx, authToken, tokenEndpoint, tokenEndpoint, nil, 1)
+		if err == nil {
+			err = json.NewDecoder(reader).Decode(&accessToken)
+		}
+	}
+
+	c.mu.Lock()
+	if err == nil && c.generation == renewal.generation {
 		c.nextRenewal = time.Now().Add(time.Duration(accessToken.Expiration) * time.Second)
 		c.accessToken = accessToken
 	}
-	return c.accessToken.Token, nil
-}
+	if c.renewal == renewal {
+		c.renewal = nil
+	}
+	c.mu.Unlock()
 
-// doAuthenticatedRequest wraps doRequest() with a call to retrieve the currently valid access token to perform the request.
-func (c *client) doAuthenticatedRequest(ctx context.Context, endpoint string, params url.Values) (io.Reader, error) {
-	token, err := c.getAccessToken()
-	if err != nil {
-		return nil, err
+	// the logger and metrics are called without holding the lock, so that slow implementations do not block other requests
+	if c.metrics != nil {
+		c.metrics.ObserveTokenRenewal(err == nil)
+	}
+	if c.logger != nil {
+		if err != nil {
+			c.logger.Error("apple maps access token renewal failed", "error", err)
+		} else {
+			c.logger.Info("apple maps access token renewed", "expiresInSeconds", accessToken.Expiration)
+		}
 	}
-	return c.doRequest(ctx, token, endpoint, params)
+	renewal.token, renewal.err = accessToken.Token, err
+	close(renewal.done)
 }
 
-// doRequest performs the http request, given the access token, api endpoint and query parameters.
-func (c *client) doRequest(ctx context.Context, auth string, endpoint string, params url.Values) (io.Reader, error) {
-	path, err := url.JoinPath(c.baseURL, endpoint)
-	if err != nil {
-		return nil, err
+// invalidateAccessToken forces the renewal of the access token on the next request,
+// unless the given token has already been replaced by a newer one.
+func (c *client) invalidateAccessToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.accessToken.Token == token {
+		c.nextRenewal = time.Now()
 	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
-	if err != nil {
-		return nil, err
+}
+
+// doAuthenticatedRequest wraps doRequest() with a call to retrieve the currently valid access token to perform the request.
+// The endpoint names the API endpoint, while the path is the URL path of the request relative to the base URL,
+// which differs from the endpoint for endpoints with path parameters.
+// Failed attempts are retried according to the client's RetryPolicy. An unauthorized response always discards the
+// cached access token, and the request is attempted once more with a freshly requested one, regardless of the policy.
+func (c *client) doAuthenticatedRequest(ctx context.Context, endpoint, path string, params url.Values) (io.Reader, error) {
+	var renewed bool
+	for attempt := 1; ; attempt++ {
+		token, err := c.getAccessToken(ctx)
+		if err == nil && c.limiter != nil {
+			err = c.limiter.acquire(ctx, endpoint)
+		}
+		var reader io.Reader
+		if err == nil {
+			reader, err = c.doRequest(ctx, token, endpoint, path, params, attempt)
+		}
+		if err == nil {
+			return reader, nil
+		}
+
+		var apiErr *APIError
+		unauthorized := errors.As(err, &apiErr) &&
+			apiErr.StatusCode == http.StatusUnauthorized && apiErr.Endpoint != tokenEndpoint
+		if unauthorized {
+			c.invalidateAccessToken(token)
+		}
+		reauth := unauthorized && !renewed
+		if !reauth && (!c.retryPolicy.isRetryable(err) || attempt >= c.retryPolicy.MaxAttempts) {
+			return nil, err
+		}
+
+		delay := c.retryPolicy.delay(attempt, err)
+		if reauth {
+			renewed = true
+			delay = 0
+		}
+		if c.metrics != nil {
+			c.metrics.ObserveRetry(endpoint)
+		}
+		if c.logger != nil {
+			c.logger.Info("retrying apple maps request", "endpoint", endpoint, "attempt", attempt+1, "delay", delay, "error", err)
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
 	}
+}
 
-	req.Header.Set("Authorization", "Bearer "+auth)
-	if params != nil {
-		req.URL.RawQuery = params.Encode()
+// doRequest performs the http request through the middleware chain, given the access token, api endpoint, path,
+// query parameters and the number of the attempt.
+func (c *client) doRequest(ctx context.Context, auth string, endpoint, path string, params url.Values, attempt int) (io.Reader, error) {
+	req := &Request{
+		Endpoint: endpoint,
+		Path:     path,
+		Values:   cloneValues(params),
+		Header:   http.Header{},
+		Attempt:  attempt,
 	}
+	req.Header.Set("Authorization", "Bearer "+auth)
 
-	res, err := c.client.Do(req)
+	res, err := c.handler()(ctx, req)
 	if err != nil {
 		return nil, err
 	}
-	switch res.StatusCode {
-	case http.StatusOK:
-		return res.Body, nil
-	case http.StatusUnauthorized:
-		return nil, fmt.Errorf("unauthorized")
-	case http.StatusBadRequest:
-		return nil, fmt.Errorf("bad request: %s", unmarshalErrorResponse(res.Body).Error.Message)
-	case http.StatusTooManyRequests:
-		return nil, fmt.Errorf("API rate limit reached: %s", unmarshalErrorResponse(res.Body).Error.Message)
-	default:
-		errRes := unmarshalErrorResponse(res.Body)
-		return res.Body, fmt.Errorf("API Error %d, Message: %s, Details: %s", res.StatusCode, errRes.Error.Message, errRes.Error.Details)
+	if res == nil {
+		return nil, fmt.Errorf("no response for %s request", endpoint)
+	}
+	if res.Body == nil {
+		return nil, fmt.Errorf("%w: no response body for %s request", ErrUnexpectedResponse, endpoint)
 	}
+	return res.Body, nil
 }
 
 // SetAuthToken sets a new JWT for the Apple Maps Client.
 // You can use this method to set a new token when the old one is about to expire.
+// It is safe to call SetAuthToken concurrently with requests.
+// If the client was created with a TokenSource, the token source is replaced by the given token.
+// Requests waiting for an access token exchanged for the previous auth token exchange the new one instead.
 func (c *client) SetAuthToken(authToken string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.authToken = authToken
+	c.tokenSource = nil
+	c.generation++
 	c.nextRenewal = time.Now()
+	c.renewal = nil
 }
 
 // exec is a generic wrapper around doAuthenticatedRequest(), that decodes the returned data into the specified
 // type T, and returns a pointer to T.
 func exec[T any](ctx context.Context, c *client, endpoint string, values url.Values) (*T, error) {
+	return execPath[T](ctx, c, endpoint, endpoint, values)
+}
+
+// execPath works like exec(), for endpoints whose URL path differs from the endpoint name.
+func execPath[T any](ctx context.Context, c *client, endpoint, path string, values url.Values) (*T, error) {
 	var res = new(T)
-	reader, err := c.doAuthenticatedRequest(ctx, endpoint, values)
+	if err := validateParameters(values); err != nil {
+		return res, err
+	}
+	reader, err := c.doAuthenticatedRequest(ctx, endpoint, path, values)
 	if err != nil {
 		return res, err
 	}
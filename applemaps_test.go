@@ -1,8 +1,11 @@
 package applemaps
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -25,16 +28,16 @@ func TestGetAccessToken_Unauthorized(t *testing.T) {
 
 	// manually instantiating the client here to test the unexported getAccessToken() method in isolation
 	mapsClient := &client{
-		testServer.Client(),
-		AccessToken{
+		client: testServer.Client(),
+		accessToken: AccessToken{
 			Token:      "jwt",
 			Expiration: 1800,
 		},
-		"",
-		time.Now(),
-		apiBase,
+		authToken:   "",
+		nextRenewal: time.Now(),
+		baseURL:     apiBase,
 	}
-	_, err := mapsClient.getAccessToken()
+	_, err := mapsClient.getAccessToken(context.Background())
 	assert.Error(t, err)
 	assert.Empty(t, mapsClient.authToken)
 }
@@ -46,16 +49,16 @@ func TestGetAccessToken_NoRefresh(t *testing.T) {
 	// manually instantiating the client here to test the unexported getAccessToken() method in isolation
 	nextRenewal := time.Now().Add(30 * time.Second)
 	mapsClient := &client{
-		testServer.Client(),
-		AccessToken{
+		client: testServer.Client(),
+		accessToken: AccessToken{
 			Token:      "the.old.jwt",
 			Expiration: 1800,
 		},
-		"authToken",
-		nextRenewal,
-		testServer.URL,
+		authToken:   "authToken",
+		nextRenewal: nextRenewal,
+		baseURL:     testServer.URL,
 	}
-	_, err := mapsClient.getAccessToken()
+	_, err := mapsClient.getAccessToken(context.Background())
 	assert.NoError(t, err)
 	assert.Equal(t, "the.old.jwt", mapsClient.accessToken.Token)
 	assert.Equal(t, nextRenewal, mapsClient.nextRenewal)
@@ -72,17 +75,17 @@ func TestGetAccessToken_Refresh(t *testing.T) {
 	// manually instantiating the client here to test the unexported getAccessToken() method in isolation
 	nextRenewal := time.Now().Add(5 * time.Second)
 	mapsClient := &client{
-		testServer.Client(),
-		AccessToken{
+		client: testServer.Client(),
+		accessToken: AccessToken{
 			Token:      "the.old.jwt",
 			Expiration: 1800,
 		},
-		"authToken",
-		nextRenewal,
-		testServer.URL,
+		authToken:   "authToken",
+		nextRenewal: nextRenewal,
+		baseURL:     testServer.URL,
 	}
 
-	tok, err := mapsClient.getAccessToken()
+	tok, err := mapsClient.getAccessToken(context.Background())
 	assert.NoError(t, err)
 	assert.Equal(t, "thisis.thejwt.token", tok)
 	assert.Equal(t, "thisis.thejwt.token", mapsClient.accessToken.Token)
@@ -91,15 +94,119 @@ func TestGetAccessToken_Refresh(t *testing.T) {
 
 func TestSetAuthToken(t *testing.T) {
 	mapsClient := &client{
-		http.DefaultClient,
-		AccessToken{
+		client: http.DefaultClient,
+		accessToken: AccessToken{
 			Token:      "jwt",
 			Expiration: 1800,
 		},
-		"old-token",
-		time.Now(),
-		"url",
+		authToken:   "old-token",
+		nextRenewal: time.Now(),
+		baseURL:     "url",
 	}
 	mapsClient.SetAuthToken("new-token")
 	assert.Equal(t, "new-token", mapsClient.authToken)
 }
+
+func TestGetAccessToken_SingleFlight(t *testing.T) {
+	var calls int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	}))
+	defer testServer.Close()
+
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL)).(*client)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tok, err := mapsClient.getAccessToken(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, "thisis.thejwt.token", tok)
+		}()
+	}
+	wg.Wait()
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+func TestGetAccessToken_ContextCanceled(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer testServer.Close()
+
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL)).(*client)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := mapsClient.getAccessToken(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, mapsClient.renewal)
+}
+
+func TestSetAuthToken_Concurrent(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	}))
+	defer testServer.Close()
+
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL)).(*client)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			mapsClient.SetAuthToken("new-token")
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := mapsClient.getAccessToken(context.Background())
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSetAuthToken_DuringRenewal(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer old-token" {
+			close(started)
+			<-release
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(accessToken_UnauthorizedResponse))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(search_SuccessResponse))
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	mapsClient := NewAppleMaps(testServer.Client(), "old-token", WithCustomURL(testServer.URL)).(*client)
+
+	// the exchange of the old token is still running when it is replaced
+	renewed := make(chan error)
+	go func() {
+		_, err := mapsClient.getAccessToken(context.Background())
+		renewed <- err
+	}()
+	<-started
+	mapsClient.SetAuthToken("new-token")
+	time.AfterFunc(10*time.Millisecond, func() { close(release) })
+
+	_, err := mapsClient.Search(context.Background(), "coffee")
+	assert.NoError(t, err)
+
+	assert.NoError(t, <-renewed)
+}
@@ -0,0 +1,143 @@
+package applemaps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// MaxEtaDestinations is the maximum number of destinations the API accepts in a single Etas request.
+const MaxEtaDestinations = 10
+
+// defaultBatchConcurrency is the number of concurrent requests used by EtasBatch if no concurrency is given.
+const defaultBatchConcurrency = 4
+
+// EtaBatchResponse contains the results of EtasBatch.
+type EtaBatchResponse struct {
+	// Etas contains one entry per destination, in the order of the destinations passed to EtasBatch.
+	// The entries of destinations whose request failed, or that the API returned no ETA for, are nil.
+	Etas []*Eta
+	// Errors contains the errors of the failed chunks, ordered by the index of their first destination.
+	Errors []*ChunkError
+}
+
+// ChunkError describes a failed request for the destinations with indexes Start to End-1.
+type ChunkError struct {
+	Start int
+	End   int
+	Err   error
+}
+
+func (e *ChunkError) Error() string {
+	return fmt.Sprintf("destinations %d to %d: %s", e.Start, e.End-1, e.Err)
+}
+
+func (e *ChunkError) Unwrap() error {
+	return e.Err
+}
+
+// EtasBatch returns the ETAs between the origin and any number of destinations, by splitting the destinations into
+// chunks of MaxEtaDestinations and requesting up to concurrency chunks at the same time.
+// If concurrency is zero or less, a default of 4 concurrent requests is used.
+// The response is always returned. If any chunk failed, the error joins the *ChunkError of every failed chunk,
+// while the ETAs of the successful chunks are still part of the response.
+func EtasBatch(ctx context.Context, c Client, origin Location, destinations []Location, concurrency int, opts ...RequestOption) (*EtaBatchResponse, error) {
+	res := &EtaBatchResponse{Etas: make([]*Eta, len(destinations))}
+	if len(destinations) == 0 {
+		return res, errors.New("destinations cannot be empty")
+	}
+
+	chunks := splitEtaChunks(origin, destinations, res.Etas)
+	requestEtaChunks(ctx, c, chunks, concurrency, opts)
+
+	var joined []error
+	for _, chunk := range chunks {
+		if chunk.err != nil {
+			err := &ChunkError{Start: chunk.start, End: chunk.start + len(chunk.destinations), Err: chunk.err}
+			res.Errors = append(res.Errors, err)
+			joined = append(joined, err)
+		}
+	}
+	return res, errors.Join(joined...)
+}
+
+// etaChunk is a single Etas request of a batch, storing its ETAs in target.
+type etaChunk struct {
+	origin       Location
+	destinations []Location
+	target       []*Eta
+	start        int
+	err          error
+}
+
+// splitEtaChunks splits the destinations into chunks of at most MaxEtaDestinations,
+// whose ETAs are stored in the matching entries of target.
+func splitEtaChunks(origin Location, destinations []Location, target []*Eta) []*etaChunk {
+	var chunks []*etaChunk
+	for start := 0; start < len(destinations); start += MaxEtaDestinations {
+		end := start + MaxEtaDestinations
+		if end > len(destinations) {
+			end = len(destinations)
+		}
+		chunks = append(chunks, &etaChunk{
+			origin:       origin,
+			destinations: destinations[start:end],
+			target:       target[start:end],
+			start:        start,
+		})
+	}
+	return chunks
+}
+
+// requestEtaChunks requests the ETAs of all chunks, with up to concurrency requests at the same time.
+// If concurrency is zero or less, a default of 4 concurrent requests is used.
+// Chunks that cannot be started because the context is done fail with the context error.
+func requestEtaChunks(ctx context.Context, c Client, chunks []*etaChunk, concurrency int, opts []RequestOption) {
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for _, chunk := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			chunk.err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(chunk *etaChunk) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			etas, err := c.Etas(ctx, chunk.origin, chunk.destinations, opts...)
+			if err != nil {
+				chunk.err = err
+				return
+			}
+			mergeEtas(chunk.target, chunk.destinations, etas.Etas)
+		}(chunk)
+	}
+	// chunks write to disjoint targets, so no further synchronization is needed
+	wg.Wait()
+}
+
+// mergeEtas assigns the ETAs of a chunk to the entries of their destinations.
+// The ETAs are assigned in order if the API returned one ETA per destination, otherwise they are matched by destination.
+func mergeEtas(target []*Eta, destinations []Location, etas []Eta) {
+	if len(etas) == len(destinations) {
+		for i := range etas {
+			target[i] = &etas[i]
+		}
+		return
+	}
+	for i := range etas {
+		for j, destination := range destinations {
+			if target[j] == nil && etas[i].Destination == destination {
+				target[j] = &etas[i]
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package applemaps
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newEtasTestServer returns a server answering Etas requests with a distance of 1000 meters per degree of latitude
+// between the origin and each destination. Requests containing a destination with a latitude of -89 fail.
+func newEtasTestServer(t *testing.T, maxConcurrent *int32) *httptest.Server {
+	var running int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/etas", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			max := atomic.LoadInt32(maxConcurrent)
+			if n <= max || atomic.CompareAndSwapInt32(maxConcurrent, max, n) {
+				break
+			}
+		}
+
+		var res EtaResponse
+		originLat, _ := strconv.ParseFloat(strings.Split(r.URL.Query().Get("origin"), ",")[0], 64)
+		destinations := strings.Split(r.URL.Query().Get("destinations"), "|")
+		assert.LessOrEqual(t, len(destinations), MaxEtaDestinations)
+		for _, d := range destinations {
+			lat, _ := strconv.ParseFloat(strings.Split(d, ",")[0], 64)
+			if lat == -89 {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(badRequest_ErrorResponse))
+				return
+			}
+			res.Etas = append(res.Etas, Eta{Destination: NewLocation(lat, 0), DistanceMeters: int(math.Abs(lat-originLat)) * 1000})
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestEtasBatch(t *testing.T) {
+	var maxConcurrent int32
+	testServer := newEtasTestServer(t, &maxConcurrent)
+	defer testServer.Close()
+
+	destinations := make([]Location, 35)
+	for i := range destinations {
+		destinations[i] = NewLocation(float64(i), 0)
+	}
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL))
+	res, err := EtasBatch(context.Background(), mapsClient, NewLocation(0, 0), destinations, 2)
+	assert.NoError(t, err)
+	assert.Empty(t, res.Errors)
+	assert.Len(t, res.Etas, 35)
+	for i, eta := range res.Etas {
+		assert.Equal(t, i*1000, eta.DistanceMeters)
+	}
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxConcurrent), int32(2))
+}
+
+func TestEtasBatch_PartialFailure(t *testing.T) {
+	var maxConcurrent int32
+	testServer := newEtasTestServer(t, &maxConcurrent)
+	defer testServer.Close()
+
+	destinations := make([]Location, 25)
+	for i := range destinations {
+		destinations[i] = NewLocation(float64(i), 0)
+	}
+	destinations[12] = NewLocation(-89, 0)
+
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL))
+	res, err := EtasBatch(context.Background(), mapsClient, NewLocation(0, 0), destinations, 0)
+	assert.ErrorIs(t, err, ErrBadRequest)
+	assert.Len(t, res.Errors, 1)
+	assert.Equal(t, 10, res.Errors[0].Start)
+	assert.Equal(t, 20, res.Errors[0].End)
+	for i, eta := range res.Etas {
+		if i >= 10 && i < 20 {
+			assert.Nil(t, eta)
+		} else {
+			assert.Equal(t, i*1000, eta.DistanceMeters)
+		}
+	}
+}
+
+func TestEtasBatch_Empty(t *testing.T) {
+	_, err := EtasBatch(context.Background(), NewAppleMaps(http.DefaultClient, "jwt"), NewLocation(0, 0), nil, 1)
+	assert.Error(t, err)
+}
+
+func TestMergeEtas_ByDestination(t *testing.T) {
+	destinations := []Location{NewLocation(1, 1), NewLocation(2, 2), NewLocation(3, 3)}
+	target := make([]*Eta, 3)
+	mergeEtas(target, destinations, []Eta{{Destination: NewLocation(3, 3)}, {Destination: NewLocation(1, 1)}})
+	assert.Equal(t, NewLocation(1, 1), target[0].Destination)
+	assert.Nil(t, target[1])
+	assert.Equal(t, NewLocation(3, 3), target[2].Destination)
+}
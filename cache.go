@@ -0,0 +1,227 @@
+package applemaps
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Cache stores encoded API responses for the CachingClient.
+// Implementations must be safe for concurrent use. See LRUCache for an in-memory implementation.
+type Cache interface {
+	// Get returns the value stored for key, and whether it was found and has not expired yet.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores the value for key, to expire after the given ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// CacheStats contains the number of cache hits and misses of a CachingClient.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// CachingClient is a Client that caches responses of another Client.
+// Responses are cached per endpoint and request parameters, including all RequestOptions.
+// Only endpoints with a TTL are cached, by default geocode, reverseGeocode and search.
+// Failed requests and errors of the Cache are never cached, the latter are treated like cache misses.
+type CachingClient struct {
+	Client
+	cache    Cache
+	ttls     map[string]time.Duration
+	observer func(endpoint string, hit bool)
+	hits     uint64
+	misses   uint64
+}
+
+type CacheOption func(c *CachingClient)
+
+// NewCachingClient wraps the given Client with a caching layer backed by cache.
+func NewCachingClient(next Client, cache Cache, options ...CacheOption) *CachingClient {
+	c := &CachingClient{
+		Client: next,
+		cache:  cache,
+		ttls: map[string]time.Duration{
+			geocodeEndpoint:        24 * time.Hour,
+			reverseGeocodeEndpoint: 24 * time.Hour,
+			searchEndpoint:         time.Hour,
+		},
+	}
+	for _, o := range options {
+		o(c)
+	}
+	return c
+}
+
+// WithCacheTTL returns a CacheOption that sets the time responses of the given endpoint are cached for,
+// for example WithCacheTTL("search", 10*time.Minute). A ttl of zero disables caching for the endpoint.
+func WithCacheTTL(endpoint string, ttl time.Duration) CacheOption {
+	return func(c *CachingClient) {
+		c.ttls[endpoint] = ttl
+	}
+}
+
+// WithCacheObserver returns a CacheOption that sets a function called on every cache hit or miss.
+func WithCacheObserver(observer func(endpoint string, hit bool)) CacheOption {
+	return func(c *CachingClient) {
+		c.observer = observer
+	}
+}
+
+// Stats returns the number of cache hits and misses since the CachingClient was created.
+func (c *CachingClient) Stats() CacheStats {
+	return CacheStats{Hits: atomic.LoadUint64(&c.hits), Misses: atomic.LoadUint64(&c.misses)}
+}
+
+// Geocode returns the latitude and longitude of the specified address.
+func (c *CachingClient) Geocode(ctx context.Context, query string, opts ...RequestOption) ([]Place, error) {
+	values := url.Values{}
+	values.Add("q", query)
+	return cached(ctx, c, geocodeEndpoint, values, opts, func() ([]Place, error) {
+		return c.Client.Geocode(ctx, query, opts...)
+	})
+}
+
+// ReverseGeocode returns a slice of addresses present at the specified location coordinates.
+func (c *CachingClient) ReverseGeocode(ctx context.Context, location Location, opts ...RequestOption) ([]Place, error) {
+	values := url.Values{}
+	values.Add("loc", location.String())
+	return cached(ctx, c, reverseGeocodeEndpoint, values, opts, func() ([]Place, error) {
+		return c.Client.ReverseGeocode(ctx, location, opts...)
+	})
+}
+
+// Search performs a search to find places that match specific criteria.
+func (c *CachingClient) Search(ctx context.Context, query string, opts ...RequestOption) (*SearchResponse, error) {
+	values := url.Values{}
+	values.Add("q", query)
+	return cached(ctx, c, searchEndpoint, values, opts, func() (*SearchResponse, error) {
+		return c.Client.Search(ctx, query, opts...)
+	})
+}
+
+// SearchAutocomplete performs a request to find results for places that you can use to autocomplete searches.
+func (c *CachingClient) SearchAutocomplete(ctx context.Context, query string, opts ...RequestOption) (*SearchAutocompleteResult, error) {
+	values := url.Values{}
+	values.Add("q", query)
+	return cached(ctx, c, searchAutocompleteEndpoint, values, opts, func() (*SearchAutocompleteResult, error) {
+		return c.Client.SearchAutocomplete(ctx, query, opts...)
+	})
+}
+
+// ResolveCompletion performs the search of an autocomplete result returned by SearchAutocomplete.
+// Resolved completions share the cache entries of the search endpoint.
+func (c *CachingClient) ResolveCompletion(ctx context.Context, result AutocompleteResult) (*SearchResponse, error) {
+	completion, err := url.Parse(result.CompletionUrl)
+	if err != nil || !strings.HasSuffix(completion.Path, "/"+searchEndpoint) {
+		return c.Client.ResolveCompletion(ctx, result)
+	}
+	return cached(ctx, c, searchEndpoint, completion.Query(), nil, func() (*SearchResponse, error) {
+		return c.Client.ResolveCompletion(ctx, result)
+	})
+}
+
+// Directions returns directions between origin and destination.
+//
+// Deprecated: Use DirectionsBetween. Directions parses origin and destination using NewWaypoint.
+func (c *CachingClient) Directions(ctx context.Context, origin, destination string, opts ...RequestOption) (*DirectionsResponse, error) {
+	return c.DirectionsBetween(ctx, NewWaypoint(origin), NewWaypoint(destination), opts...)
+}
+
+// DirectionsBetween returns directions between origin and destination.
+func (c *CachingClient) DirectionsBetween(ctx context.Context, origin, destination Waypoint, opts ...RequestOption) (*DirectionsResponse, error) {
+	values := url.Values{}
+	values.Add("origin", origin.cacheValue())
+	values.Add("destination", destination.cacheValue())
+	return cached(ctx, c, directionsEndpoint, values, opts, func() (*DirectionsResponse, error) {
+		return c.Client.DirectionsBetween(ctx, origin, destination, opts...)
+	})
+}
+
+// Etas returns the estimated time of arrival (ETA) and distance between origin and destination locations.
+func (c *CachingClient) Etas(ctx context.Context, origin Location, destinations []Location, opts ...RequestOption) (*EtaResponse, error) {
+	values := url.Values{}
+	values.Add("origin", origin.String())
+	values.Add("destinations", queryParameterString(destinations))
+	return cached(ctx, c, etasEndpoint, values, opts, func() (*EtaResponse, error) {
+		return c.Client.Etas(ctx, origin, destinations, opts...)
+	})
+}
+
+// Place returns the place with the given ID.
+func (c *CachingClient) Place(ctx context.Context, id string, opts ...RequestOption) (*Place, error) {
+	values := url.Values{}
+	values.Add("id", id)
+	return cached(ctx, c, placeEndpoint, values, opts, func() (*Place, error) {
+		return c.Client.Place(ctx, id, opts...)
+	})
+}
+
+// Places returns the places with the given IDs.
+func (c *CachingClient) Places(ctx context.Context, ids []string, opts ...RequestOption) (*PlacesResponse, error) {
+	values := url.Values{}
+	values.Add("ids", queryParameterString(ids))
+	return cached(ctx, c, placeEndpoint, values, opts, func() (*PlacesResponse, error) {
+		return c.Client.Places(ctx, ids, opts...)
+	})
+}
+
+// PlaceAlternateIDs returns all alternate IDs of the places with the given IDs.
+func (c *CachingClient) PlaceAlternateIDs(ctx context.Context, ids []string, opts ...RequestOption) (*AlternateIDsResponse, error) {
+	values := url.Values{}
+	values.Add("ids", queryParameterString(ids))
+	return cached(ctx, c, alternateIDsEndpoint, values, opts, func() (*AlternateIDsResponse, error) {
+		return c.Client.PlaceAlternateIDs(ctx, ids, opts...)
+	})
+}
+
+// observe counts a cache hit or miss and reports it to the observer.
+func (c *CachingClient) observe(endpoint string, hit bool) {
+	if hit {
+		atomic.AddUint64(&c.hits, 1)
+	} else {
+		atomic.AddUint64(&c.misses, 1)
+	}
+	if c.observer != nil {
+		c.observer(endpoint, hit)
+	}
+}
+
+// cached returns the cached response for the endpoint and query parameters if there is one,
+// otherwise it calls fetch and caches its result.
+func cached[T any](ctx context.Context, c *CachingClient, endpoint string, values url.Values, opts []RequestOption, fetch func() (T, error)) (T, error) {
+	ttl := c.ttls[endpoint]
+	if ttl <= 0 {
+		return fetch()
+	}
+
+	for _, opt := range opts {
+		opt(values)
+	}
+	key := cacheKey(endpoint, values)
+	if data, ok, err := c.cache.Get(ctx, key); err == nil && ok {
+		var res T
+		if err := json.Unmarshal(data, &res); err == nil {
+			c.observe(endpoint, true)
+			return res, nil
+		}
+	}
+
+	c.observe(endpoint, false)
+	res, err := fetch()
+	if err != nil {
+		return res, err
+	}
+	if data, err := json.Marshal(res); err == nil {
+		_ = c.cache.Set(ctx, key, data, ttl)
+	}
+	return res, nil
+}
+
+// cacheKey builds the cache key from the endpoint and the query parameters, sorted by key.
+func cacheKey(endpoint string, values url.Values) string {
+	return endpoint + "?" + values.Encode()
+}
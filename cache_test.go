@@ -0,0 +1,105 @@
+package applemaps
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheTestSuite struct {
+	suite.Suite
+	testServer    *httptest.Server
+	geocodeCalls  int32
+	etasCalls     int32
+	observedHits  int32
+	observedMiss  int32
+	cache         *LRUCache
+	cachingClient *CachingClient
+}
+
+func (s *CacheTestSuite) SetupTest() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/geocode", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&s.geocodeCalls, 1)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(geocode_SuccessResponse))
+	})
+	mux.HandleFunc("/etas", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&s.etasCalls, 1)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(etas_SuccessResponse))
+	})
+	s.testServer = httptest.NewServer(mux)
+	s.geocodeCalls, s.etasCalls, s.observedHits, s.observedMiss = 0, 0, 0, 0
+	s.cache = NewLRUCache(10)
+	s.cachingClient = NewCachingClient(
+		NewAppleMaps(s.testServer.Client(), "jwt", WithCustomURL(s.testServer.URL)),
+		s.cache,
+		WithCacheObserver(func(endpoint string, hit bool) {
+			if hit {
+				atomic.AddInt32(&s.observedHits, 1)
+			} else {
+				atomic.AddInt32(&s.observedMiss, 1)
+			}
+		}),
+	)
+}
+
+func (s *CacheTestSuite) TearDownTest() {
+	s.testServer.Close()
+}
+
+func (s *CacheTestSuite) TestGeocode_Cached() {
+	var expected = &SearchResponse{}
+	json.Unmarshal([]byte(geocode_SuccessResponse), expected)
+
+	for i := 0; i < 3; i++ {
+		res, err := s.cachingClient.Geocode(context.Background(), "test query", WithUserLocation(NewLocation(1, 1)), WithLimitToCountries("DE"))
+		s.NoError(err)
+		s.Equal(expected.Results, res)
+	}
+	// the same options in a different order share the cache entry
+	_, err := s.cachingClient.Geocode(context.Background(), "test query", WithLimitToCountries("DE"), WithUserLocation(NewLocation(1, 1)))
+	s.NoError(err)
+	s.EqualValues(1, s.geocodeCalls)
+	s.Equal(CacheStats{Hits: 3, Misses: 1}, s.cachingClient.Stats())
+	s.EqualValues(3, s.observedHits)
+	s.EqualValues(1, s.observedMiss)
+
+	_, err = s.cachingClient.Geocode(context.Background(), "other query")
+	s.NoError(err)
+	s.EqualValues(2, s.geocodeCalls)
+	s.Equal(2, s.cache.Len())
+}
+
+func (s *CacheTestSuite) TestEtas_NotCached() {
+	for i := 0; i < 2; i++ {
+		_, err := s.cachingClient.Etas(context.Background(), NewLocation(1, 1), []Location{NewLocation(1, 1)})
+		s.NoError(err)
+	}
+	s.EqualValues(2, s.etasCalls)
+	s.Equal(CacheStats{}, s.cachingClient.Stats())
+}
+
+func (s *CacheTestSuite) TestEtas_CacheTTL() {
+	cachingClient := NewCachingClient(s.cachingClient.Client, s.cache, WithCacheTTL(etasEndpoint, time.Minute))
+	for i := 0; i < 2; i++ {
+		_, err := cachingClient.Etas(context.Background(), NewLocation(1, 1), []Location{NewLocation(1, 1)})
+		s.NoError(err)
+	}
+	s.EqualValues(1, s.etasCalls)
+}
+
+func TestCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(CacheTestSuite))
+}
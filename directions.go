@@ -3,6 +3,7 @@ package applemaps
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/url"
 )
 
@@ -17,21 +18,67 @@ func (a Avoid) String() string {
 // Directions returns directions between origin and destination.
 // Both origin and destination can be specified as either an address string or coordinates in the format "Lat|Lon",
 // for example origin="37.7857,-122.4011"
+//
+// Deprecated: Use DirectionsBetween, which also accepts place IDs. Directions parses origin and destination using NewWaypoint.
 func (c *client) Directions(ctx context.Context, origin, destination string, opts ...RequestOption) (*DirectionsResponse, error) {
+	return c.DirectionsBetween(ctx, NewWaypoint(origin), NewWaypoint(destination), opts...)
+}
+
+// DirectionsBetween returns directions between origin and destination.
+// Both waypoints are validated before sending the request. Waypoints given as place ID are resolved
+// to the coordinates of the place first.
+func (c *client) DirectionsBetween(ctx context.Context, origin, destination Waypoint, opts ...RequestOption) (*DirectionsResponse, error) {
+	if err := origin.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid origin: %w", err)
+	}
+	if err := destination.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid destination: %w", err)
+	}
+	originParameter, err := c.waypointParameter(ctx, origin)
+	if err != nil {
+		return nil, err
+	}
+	destinationParameter, err := c.waypointParameter(ctx, destination)
+	if err != nil {
+		return nil, err
+	}
+
 	values := url.Values{}
-	values.Add("origin", origin)
-	values.Add("destination", destination)
+	values.Add("origin", originParameter)
+	values.Add("destination", destinationParameter)
 	for _, opt := range opts {
 		opt(values)
 	}
 	return exec[DirectionsResponse](ctx, c, directionsEndpoint, values)
 }
 
+// waypointParameter returns the value of the origin or destination parameter for the waypoint,
+// looking up the coordinates of waypoints given as place ID.
+func (c *client) waypointParameter(ctx context.Context, w Waypoint) (string, error) {
+	if w.location != nil || w.placeID == "" {
+		return w.String(), nil
+	}
+	place, err := c.Place(ctx, w.placeID)
+	if err != nil {
+		return "", fmt.Errorf("look up place %s: %w", w.placeID, err)
+	}
+	return place.Coordinate.String(), nil
+}
+
 // Etas returns the estimated time of arrival (ETA) and distance between origin and destination locations.
+// The API accepts up to MaxEtaDestinations destinations per request, use EtasBatch for more destinations.
 func (c *client) Etas(ctx context.Context, origin Location, destinations []Location, opts ...RequestOption) (*EtaResponse, error) {
 	if len(destinations) == 0 {
 		return nil, errors.New("destinations cannot be empty")
 	}
+	if err := origin.Validate(); err != nil {
+		return nil, fmt.Errorf("origin: %w", err)
+	}
+	for i, d := range destinations {
+		if err := d.Validate(); err != nil {
+			return nil, fmt.Errorf("destination %d: %w", i, err)
+		}
+	}
 	values := url.Values{}
 	values.Add("origin", origin.String())
 	values.Add("destinations", queryParameterString(destinations))
@@ -1,10 +1,84 @@
 package applemaps
 
 import (
+	"bytes"
 	"encoding/json"
-	"io"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
+var (
+	// ErrUnauthorized is returned when the API rejects the auth token or the access token (HTTP 401).
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrBadRequest is returned when the API rejects the request parameters (HTTP 400).
+	ErrBadRequest = errors.New("bad request")
+	// ErrNotFound is returned when the requested resource does not exist (HTTP 404).
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited is returned when the API rate limit or daily quota has been reached (HTTP 429).
+	ErrRateLimited = errors.New("API rate limit reached")
+	// ErrUnexpectedResponse is returned when a successful response does not contain JSON, for example an HTML page
+	// returned by a proxy.
+	ErrUnexpectedResponse = errors.New("unexpected response")
+	// ErrResponseTooLarge is returned when a response body exceeds the maximum size configured for the client.
+	ErrResponseTooLarge = errors.New("response body too large")
+	// ErrInvalidCoordinates is returned when a Location or MapRegion is out of range, before sending the request,
+	// and by ParseLocation for text that cannot be parsed.
+	ErrInvalidCoordinates = errors.New("invalid coordinates")
+)
+
+// requestIDHeaders lists the response headers that may carry an identifier for the request, in order of preference.
+var requestIDHeaders = []string{"X-Request-Id", "X-Apple-Request-Uuid", "X-Apple-Request-Id"}
+
+// APIError describes a non-200 response returned by the Apple Maps Server API.
+// Use errors.Is with one of the sentinel errors (ErrUnauthorized, ErrBadRequest, ErrNotFound, ErrRateLimited)
+// to check for a specific kind of failure, or errors.As to access the details of the response.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Endpoint is the API endpoint that returned the error, for example "geocode" or "token".
+	Endpoint string
+	// Message is the error message returned by the API, if any.
+	Message string
+	// Details contains the error details returned by the API, if any.
+	Details []any
+	// RequestID is the request identifier sent back by the server, if any.
+	RequestID string
+	// RetryAfter is the delay the server asked for before retrying, parsed from the Retry-After header.
+	// It is zero if the header was not present.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	switch {
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized.Error()
+	case e.StatusCode == http.StatusBadRequest, e.StatusCode == http.StatusNotFound, e.StatusCode == http.StatusTooManyRequests:
+		return fmt.Sprintf("%s: %s", e.Unwrap(), e.Message)
+	default:
+		return fmt.Sprintf("API Error %d, Message: %s, Details: %s", e.StatusCode, e.Message, e.Details)
+	}
+}
+
+// Unwrap returns the sentinel error matching the status code of the response, or nil if there is none.
+func (e *APIError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusBadRequest:
+		return ErrBadRequest
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	default:
+		return nil
+	}
+}
+
 type errorResponse struct {
 	Error struct {
 		Message string `json:"message"`
@@ -12,8 +86,66 @@ type errorResponse struct {
 	} `json:"error"`
 }
 
-func unmarshalErrorResponse(reader io.Reader) *errorResponse {
+func unmarshalErrorResponse(body []byte) (*errorResponse, error) {
 	var errorRes = &errorResponse{}
-	_ = json.NewDecoder(reader).Decode(errorRes)
-	return errorRes
+	err := json.Unmarshal(body, errorRes)
+	return errorRes, err
+}
+
+// newAPIError creates an APIError from a non-200 response, decoding the error body and reading the relevant headers.
+// If the body is not a JSON error response, for example an HTML page returned by a proxy,
+// the message describes the content type and the beginning of the body instead.
+func newAPIError(endpoint string, res *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: res.StatusCode,
+		Endpoint:   endpoint,
+		RetryAfter: parseRetryAfter(res.Header.Get("Retry-After"), time.Now()),
+	}
+	if errRes, err := unmarshalErrorResponse(body); err == nil {
+		apiErr.Message = errRes.Error.Message
+		apiErr.Details = errRes.Error.Details
+	} else if len(bytes.TrimSpace(body)) > 0 {
+		apiErr.Message = describeBody(res.Header.Get("Content-Type"), body)
+	}
+	for _, h := range requestIDHeaders {
+		if id := res.Header.Get(h); id != "" {
+			apiErr.RequestID = id
+			break
+		}
+	}
+	return apiErr
+}
+
+// describeBody returns a short description of an unexpected response body, for use in error messages.
+func describeBody(contentType string, body []byte) string {
+	const maxLength = 200
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+	text := strings.Join(strings.Fields(string(body)), " ")
+	if r := []rune(text); len(r) > maxLength {
+		text = string(r[:maxLength]) + "..."
+	}
+	return fmt.Sprintf("unexpected %s response: %s", contentType, text)
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a number of seconds or an HTTP date.
+// It returns zero if the value is empty, invalid or lies in the past.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
 }
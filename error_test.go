@@ -0,0 +1,108 @@
+package applemaps
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	badRequest_ErrorResponse string = `{"error":{"message":"Invalid query parameter","details":["q"]}}`
+)
+
+func TestAPIError_Sentinels(t *testing.T) {
+	type test struct {
+		statusCode int
+		expected   error
+	}
+	tt := map[string]test{
+		"Unauthorized": {http.StatusUnauthorized, ErrUnauthorized},
+		"Bad Request":  {http.StatusBadRequest, ErrBadRequest},
+		"Not Found":    {http.StatusNotFound, ErrNotFound},
+		"Rate Limited": {http.StatusTooManyRequests, ErrRateLimited},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			var err error = &APIError{StatusCode: tc.statusCode}
+			assert.ErrorIs(t, err, tc.expected)
+		})
+	}
+
+	var err error = &APIError{StatusCode: http.StatusInternalServerError}
+	assert.NotErrorIs(t, err, ErrBadRequest)
+	assert.Nil(t, errors.Unwrap(err))
+}
+
+func TestAPIError_Response(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/geocode", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "request-id")
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(badRequest_ErrorResponse))
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL))
+	_, err := mapsClient.Geocode(context.Background(), "test query")
+	assert.ErrorIs(t, err, ErrBadRequest)
+	assert.EqualError(t, err, "bad request: Invalid query parameter")
+
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, geocodeEndpoint, apiErr.Endpoint)
+	assert.Equal(t, "Invalid query parameter", apiErr.Message)
+	assert.Equal(t, []any{"q"}, apiErr.Details)
+	assert.Equal(t, "request-id", apiErr.RequestID)
+	assert.Equal(t, 30*time.Second, apiErr.RetryAfter)
+}
+
+func TestAPIError_TokenExchange(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(accessToken_UnauthorizedResponse))
+	}))
+	defer testServer.Close()
+
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL))
+	_, err := mapsClient.Search(context.Background(), "test query")
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, tokenEndpoint, apiErr.Endpoint)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	type test struct {
+		value    string
+		expected time.Duration
+	}
+	tt := map[string]test{
+		"Empty":     {"", 0},
+		"Seconds":   {"120", 2 * time.Minute},
+		"Negative":  {"-1", 0},
+		"HTTP Date": {"Sat, 01 Jul 2023 12:01:00 GMT", time.Minute},
+		"Past Date": {"Sat, 01 Jul 2023 11:00:00 GMT", 0},
+		"Invalid":   {"soon", 0},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, parseRetryAfter(tc.value, now))
+		})
+	}
+}
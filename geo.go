@@ -0,0 +1,271 @@
+package applemaps
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// EarthRadius is the mean radius of the earth in meters, used by the spherical calculations.
+const EarthRadius = 6371008.8
+
+// Parameters of the WGS 84 ellipsoid, used by VincentyDistance.
+const (
+	wgs84SemiMajorAxis = 6378137.0
+	wgs84Flattening    = 1 / 298.257223563
+	wgs84SemiMinorAxis = wgs84SemiMajorAxis * (1 - wgs84Flattening)
+)
+
+// vincentyMaxIterations bounds the number of iterations of VincentyDistance.
+const vincentyMaxIterations = 200
+
+// ErrNoConvergence is returned by VincentyDistance if the formula does not converge,
+// which can happen for nearly antipodal points. Use HaversineDistance as a fallback.
+var ErrNoConvergence = errors.New("vincenty formula failed to converge")
+
+// HaversineDistance returns the great-circle distance in meters between l and to, assuming a spherical earth.
+// Its error is up to about 0.5% compared to VincentyDistance.
+func (l Location) HaversineDistance(to Location) float64 {
+	lat1, lat2 := radians(l.Latitude), radians(to.Latitude)
+	dLat := lat2 - lat1
+	dLng := radians(to.Longitude - l.Longitude)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// VincentyDistance returns the distance in meters between l and to on the WGS 84 ellipsoid,
+// using the inverse formula of Vincenty. It returns ErrNoConvergence for some nearly antipodal points.
+func (l Location) VincentyDistance(to Location) (float64, error) {
+	const a, b, f = wgs84SemiMajorAxis, wgs84SemiMinorAxis, wgs84Flattening
+
+	L := radians(to.Longitude - l.Longitude)
+	U1 := math.Atan((1 - f) * math.Tan(radians(l.Latitude)))
+	U2 := math.Atan((1 - f) * math.Tan(radians(to.Latitude)))
+	sinU1, cosU1 := math.Sincos(U1)
+	sinU2, cosU2 := math.Sincos(U2)
+
+	lambda := L
+	var sinSigma, cosSigma, sigma, cosSqAlpha, cos2SigmaM float64
+	for i := 0; ; i++ {
+		if i == vincentyMaxIterations {
+			return 0, ErrNoConvergence
+		}
+		sinLambda, cosLambda := math.Sincos(lambda)
+		sinSigma = math.Hypot(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+		if sinSigma == 0 {
+			return 0, nil
+		}
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha = 1 - sinAlpha*sinAlpha
+		cos2SigmaM = 0
+		if cosSqAlpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		}
+		C := f / 16 * cosSqAlpha * (4 + f*(4-3*cosSqAlpha))
+		previous := lambda
+		lambda = L + (1-C)*f*sinAlpha*(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda-previous) < 1e-12 {
+			break
+		}
+	}
+
+	uSq := cosSqAlpha * (a*a - b*b) / (b * b)
+	A := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	B := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+	deltaSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+		B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+	return b * A * (sigma - deltaSigma), nil
+}
+
+// Bearing returns the initial bearing in degrees from l to to, clockwise from north in the range [0, 360).
+func (l Location) Bearing(to Location) float64 {
+	lat1, lat2 := radians(l.Latitude), radians(to.Latitude)
+	dLng := radians(to.Longitude - l.Longitude)
+	y := math.Sin(dLng) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLng)
+	return math.Mod(degrees(math.Atan2(y, x))+360, 360)
+}
+
+// Destination returns the location reached by travelling the given distance in meters from l
+// along a great circle, starting with the given bearing in degrees clockwise from north.
+func (l Location) Destination(bearing, distance float64) Location {
+	lat1, lng1 := radians(l.Latitude), radians(l.Longitude)
+	theta := radians(bearing)
+	delta := distance / EarthRadius
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(delta) + math.Cos(lat1)*math.Sin(delta)*math.Cos(theta))
+	lng2 := lng1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(lat1), math.Cos(delta)-math.Sin(lat1)*math.Sin(lat2))
+	return NewLocation(degrees(lat2), wrapLongitude(degrees(lng2)))
+}
+
+// Midpoint returns the point halfway between l and to along the great circle between them.
+func (l Location) Midpoint(to Location) Location {
+	lat1, lng1, lat2 := radians(l.Latitude), radians(l.Longitude), radians(to.Latitude)
+	dLng := radians(to.Longitude - l.Longitude)
+	bx := math.Cos(lat2) * math.Cos(dLng)
+	by := math.Cos(lat2) * math.Sin(dLng)
+	lat := math.Atan2(math.Sin(lat1)+math.Sin(lat2), math.Hypot(math.Cos(lat1)+bx, by))
+	lng := lng1 + math.Atan2(by, math.Cos(lat1)+bx)
+	return NewLocation(degrees(lat), wrapLongitude(degrees(lng)))
+}
+
+// RegionFromPoints returns the smallest region containing all points. The region crosses the antimeridian
+// if that makes it smaller. It returns an empty region if there are no points.
+func RegionFromPoints(points []Location) MapRegion {
+	if len(points) == 0 {
+		return MapRegion{}
+	}
+	lngs := make([]float64, len(points))
+	r := NewRegion(points[0].Latitude, 0, points[0].Latitude, 0)
+	for i, p := range points {
+		r.NorthLatitude = math.Max(r.NorthLatitude, p.Latitude)
+		r.SouthLatitude = math.Min(r.SouthLatitude, p.Latitude)
+		lngs[i] = wrapLongitude(p.Longitude)
+	}
+	sort.Float64s(lngs)
+
+	// the region spans all longitudes except the largest gap between two consecutive points
+	r.WestLongitude, r.EastLongitude = lngs[0], lngs[len(lngs)-1]
+	largestGap := lngs[0] + 360 - lngs[len(lngs)-1]
+	for i := 1; i < len(lngs); i++ {
+		if gap := lngs[i] - lngs[i-1]; gap > largestGap {
+			largestGap = gap
+			r.WestLongitude, r.EastLongitude = lngs[i], lngs[i-1]
+		}
+	}
+	return r
+}
+
+// CrossesAntimeridian returns whether the region crosses the antimeridian, which is the case if its
+// WestLongitude is greater than its EastLongitude.
+func (r MapRegion) CrossesAntimeridian() bool {
+	return r.WestLongitude > r.EastLongitude
+}
+
+// Contains returns whether the location lies within the region, including its boundary.
+func (r MapRegion) Contains(l Location) bool {
+	if l.Latitude < r.SouthLatitude || l.Latitude > r.NorthLatitude {
+		return false
+	}
+	lng := wrapLongitude(l.Longitude)
+	if r.CrossesAntimeridian() {
+		return lng >= r.WestLongitude || lng <= r.EastLongitude
+	}
+	return lng >= r.WestLongitude && lng <= r.EastLongitude
+}
+
+// Center returns the center of the region.
+func (r MapRegion) Center() Location {
+	return NewLocation((r.NorthLatitude+r.SouthLatitude)/2, wrapLongitude(r.WestLongitude+r.longitudeSpan()/2))
+}
+
+// Expand returns the region grown by the given distance in meters on every side, or shrunk for negative distances.
+// Latitudes are clamped at the poles, and the region spans all longitudes if it reaches a pole or wraps around the earth.
+// A region shrunk by more than its size collapses to its center.
+func (r MapRegion) Expand(meters float64) MapRegion {
+	dLat := degrees(meters / EarthRadius)
+	north := math.Min(90, r.NorthLatitude+dLat)
+	south := math.Max(-90, r.SouthLatitude-dLat)
+	if south > north {
+		center := r.Center()
+		return NewRegion(center.Latitude, center.Longitude, center.Latitude, center.Longitude)
+	}
+
+	// expand the longitudes at the latitude farthest from the equator, where a degree is shortest
+	cos := math.Cos(radians(math.Max(math.Abs(north), math.Abs(south))))
+	if cos < 1e-12 {
+		return NewRegion(north, 180, south, -180)
+	}
+	dLng := degrees(meters / (EarthRadius * cos))
+	span := r.longitudeSpan() + 2*dLng
+	if span < 0 {
+		lng := r.Center().Longitude
+		return NewRegion(north, lng, south, lng)
+	}
+	return newRegionFromSpan(north, south, r.WestLongitude-dLng, span)
+}
+
+// Union returns the smallest region containing both regions.
+func (r MapRegion) Union(other MapRegion) MapRegion {
+	north := math.Max(r.NorthLatitude, other.NorthLatitude)
+	south := math.Min(r.SouthLatitude, other.SouthLatitude)
+
+	// either start at the west of r and extend to the east of other, or the other way round
+	spanR, spanOther := r.longitudeSpan(), other.longitudeSpan()
+	fromR := math.Max(spanR, longitudeOffset(r.WestLongitude, other.WestLongitude)+spanOther)
+	fromOther := math.Max(spanOther, longitudeOffset(other.WestLongitude, r.WestLongitude)+spanR)
+	if fromOther < fromR {
+		return newRegionFromSpan(north, south, other.WestLongitude, fromOther)
+	}
+	return newRegionFromSpan(north, south, r.WestLongitude, fromR)
+}
+
+// Intersect returns the region contained in both regions, and false if they do not intersect.
+// Two regions that both span more than half of the longitudes can intersect in two separate parts,
+// in which case the larger part is returned.
+func (r MapRegion) Intersect(other MapRegion) (MapRegion, bool) {
+	north := math.Min(r.NorthLatitude, other.NorthLatitude)
+	south := math.Max(r.SouthLatitude, other.SouthLatitude)
+	if south > north {
+		return MapRegion{}, false
+	}
+
+	// the parts of other relative to the west of r, which is at offset zero
+	spanR, spanOther := r.longitudeSpan(), other.longitudeSpan()
+	offset := longitudeOffset(r.WestLongitude, other.WestLongitude)
+	found := false
+	var start, span float64
+	for _, otherStart := range []float64{offset, offset - 360} {
+		partStart := math.Max(0, otherStart)
+		partEnd := math.Min(spanR, otherStart+spanOther)
+		if partEnd >= partStart && (!found || partEnd-partStart > span) {
+			found, start, span = true, partStart, partEnd-partStart
+		}
+	}
+	if !found {
+		return MapRegion{}, false
+	}
+	return newRegionFromSpan(north, south, r.WestLongitude+start, span), true
+}
+
+// longitudeSpan returns the number of degrees of longitude the region spans, taking the antimeridian into account.
+func (r MapRegion) longitudeSpan() float64 {
+	if r.CrossesAntimeridian() {
+		return r.EastLongitude + 360 - r.WestLongitude
+	}
+	return r.EastLongitude - r.WestLongitude
+}
+
+// newRegionFromSpan creates a region spanning the given number of degrees of longitude east of west.
+func newRegionFromSpan(north, south, west, span float64) MapRegion {
+	if span >= 360 {
+		return NewRegion(north, 180, south, -180)
+	}
+	return NewRegion(north, wrapLongitude(west+span), south, wrapLongitude(west))
+}
+
+// longitudeOffset returns the number of degrees east of from to reach to, in the range [0, 360).
+func longitudeOffset(from, to float64) float64 {
+	return math.Mod(math.Mod(to-from, 360)+360, 360)
+}
+
+// wrapLongitude returns the longitude in the range [-180, 180]. Longitudes within the range are returned unchanged.
+func wrapLongitude(lng float64) float64 {
+	if lng >= -180 && lng <= 180 {
+		return lng
+	}
+	lng = math.Mod(lng+180, 360)
+	if lng < 0 {
+		lng += 360
+	}
+	return lng - 180
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
@@ -0,0 +1,162 @@
+package applemaps
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	flindersPeak = NewLocation(-37.95103342, 144.42486789)
+	buninyong    = NewLocation(-37.65282114, 143.92649554)
+)
+
+func TestLocation_HaversineDistance(t *testing.T) {
+	assert.InDelta(t, 54972.271, flindersPeak.HaversineDistance(buninyong), 54972.271*0.005)
+	assert.InDelta(t, 111195, NewLocation(0, 0).HaversineDistance(NewLocation(1, 0)), 1)
+	assert.InDelta(t, 222390, NewLocation(0, 179).HaversineDistance(NewLocation(0, -179)), 1)
+	assert.Zero(t, buninyong.HaversineDistance(buninyong))
+}
+
+func TestLocation_VincentyDistance(t *testing.T) {
+	d, err := flindersPeak.VincentyDistance(buninyong)
+	assert.NoError(t, err)
+	assert.InDelta(t, 54972.271, d, 0.001)
+
+	d, err = NewLocation(0, 0).VincentyDistance(NewLocation(0, 1))
+	assert.NoError(t, err)
+	assert.InDelta(t, 111319.491, d, 0.001)
+
+	d, err = buninyong.VincentyDistance(buninyong)
+	assert.NoError(t, err)
+	assert.Zero(t, d)
+
+	_, err = NewLocation(0, 0).VincentyDistance(NewLocation(0.5, 179.7))
+	assert.ErrorIs(t, err, ErrNoConvergence)
+}
+
+func TestLocation_Bearing(t *testing.T) {
+	assert.InDelta(t, 0, NewLocation(0, 0).Bearing(NewLocation(10, 0)), 1e-9)
+	assert.InDelta(t, 90, NewLocation(0, 0).Bearing(NewLocation(0, 10)), 1e-9)
+	assert.InDelta(t, 180, NewLocation(10, 0).Bearing(NewLocation(0, 0)), 1e-9)
+	assert.InDelta(t, 270, NewLocation(0, 10).Bearing(NewLocation(0, 0)), 1e-9)
+	assert.InDelta(t, 90, NewLocation(0, 179).Bearing(NewLocation(0, -179)), 1e-9)
+	// the bearing on the ellipsoid is 306.868 degrees
+	assert.InDelta(t, 306.868, flindersPeak.Bearing(buninyong), 0.2)
+}
+
+func TestLocation_Destination(t *testing.T) {
+	d := flindersPeak.Destination(flindersPeak.Bearing(buninyong), flindersPeak.HaversineDistance(buninyong))
+	assert.InDelta(t, buninyong.Latitude, d.Latitude, 1e-9)
+	assert.InDelta(t, buninyong.Longitude, d.Longitude, 1e-9)
+
+	d = NewLocation(0, 179).Destination(90, 2*EarthRadius*math.Pi/180)
+	assert.InDelta(t, 0, d.Latitude, 1e-9)
+	assert.InDelta(t, -179, d.Longitude, 1e-9)
+
+	d = NewLocation(10, 20).Destination(45, 0)
+	assert.InDelta(t, 10, d.Latitude, 1e-9)
+	assert.InDelta(t, 20, d.Longitude, 1e-9)
+}
+
+func TestLocation_Midpoint(t *testing.T) {
+	m := NewLocation(0, 0).Midpoint(NewLocation(0, 90))
+	assert.InDelta(t, 0, m.Latitude, 1e-9)
+	assert.InDelta(t, 45, m.Longitude, 1e-9)
+
+	m = NewLocation(0, 170).Midpoint(NewLocation(0, -170))
+	assert.InDelta(t, 0, m.Latitude, 1e-9)
+	assert.InDelta(t, 180, math.Abs(m.Longitude), 1e-9)
+
+	m = flindersPeak.Midpoint(buninyong)
+	assert.InDelta(t, flindersPeak.HaversineDistance(m), m.HaversineDistance(buninyong), 1e-6)
+}
+
+func TestMapRegion_Contains(t *testing.T) {
+	r := NewRegion(10, 20, -10, -20)
+	assert.True(t, r.Contains(NewLocation(0, 0)))
+	assert.True(t, r.Contains(NewLocation(10, -20)))
+	assert.False(t, r.Contains(NewLocation(11, 0)))
+	assert.False(t, r.Contains(NewLocation(0, 21)))
+
+	// crossing the antimeridian
+	r = NewRegion(10, -170, -10, 170)
+	assert.True(t, r.CrossesAntimeridian())
+	assert.True(t, r.Contains(NewLocation(0, 180)))
+	assert.True(t, r.Contains(NewLocation(0, -175)))
+	assert.True(t, r.Contains(NewLocation(0, 175)))
+	assert.True(t, r.Contains(NewLocation(0, 185)))
+	assert.False(t, r.Contains(NewLocation(0, 0)))
+	assert.False(t, r.Contains(NewLocation(0, -160)))
+}
+
+func TestMapRegion_Center(t *testing.T) {
+	assert.Equal(t, NewLocation(5, 10), NewRegion(10, 20, 0, 0).Center())
+	assert.Equal(t, NewLocation(0, 180), NewRegion(10, -170, -10, 170).Center())
+	assert.Equal(t, NewLocation(0, -175), NewRegion(10, -160, -10, 170).Center())
+}
+
+func TestMapRegion_Expand(t *testing.T) {
+	degree := EarthRadius * math.Pi / 180
+	r := NewRegion(1, 1, -1, -1).Expand(degree)
+	assert.InDelta(t, 2, r.NorthLatitude, 1e-9)
+	assert.InDelta(t, -2, r.SouthLatitude, 1e-9)
+	assert.InDelta(t, 1+1/math.Cos(radians(2)), r.EastLongitude, 1e-9)
+	assert.InDelta(t, -1-1/math.Cos(radians(2)), r.WestLongitude, 1e-9)
+
+	r = NewRegion(1, 179.5, -1, 179).Expand(degree)
+	assert.True(t, r.CrossesAntimeridian())
+	assert.InDelta(t, 179.5+1/math.Cos(radians(2))-360, r.EastLongitude, 1e-9)
+
+	assert.Equal(t, NewRegion(90, 180, 88, -180), NewRegion(89.5, 1, 89, -1).Expand(degree))
+	assert.Equal(t, NewRegion(0, 0, 0, 0), NewRegion(1, 1, -1, -1).Expand(-2*degree))
+
+	r = NewRegion(10, 1, -10, -1).Expand(-5 * degree)
+	assert.InDelta(t, 5, r.NorthLatitude, 1e-9)
+	assert.Equal(t, r.WestLongitude, r.EastLongitude)
+}
+
+func TestMapRegion_Union(t *testing.T) {
+	assert.Equal(t, NewRegion(10, 30, -5, 0), NewRegion(10, 10, 0, 0).Union(NewRegion(5, 30, -5, 20)))
+	assert.Equal(t, NewRegion(10, 10, 0, 0), NewRegion(10, 10, 0, 0).Union(NewRegion(5, 5, 5, 5)))
+
+	// the smaller union crosses the antimeridian
+	assert.Equal(t, NewRegion(10, -150, 0, 170), NewRegion(10, 180, 0, 170).Union(NewRegion(10, -150, 0, -160)))
+	assert.Equal(t, NewRegion(10, -150, 0, 170), NewRegion(10, -150, 0, -160).Union(NewRegion(10, 180, 0, 170)))
+	assert.Equal(t, NewRegion(10, 180, 0, -180), NewRegion(10, 90, 0, -90).Union(NewRegion(10, -90, 0, 90)))
+}
+
+func TestMapRegion_Intersect(t *testing.T) {
+	r, ok := NewRegion(10, 10, 0, 0).Intersect(NewRegion(15, 20, 5, 5))
+	assert.True(t, ok)
+	assert.Equal(t, NewRegion(10, 10, 5, 5), r)
+
+	_, ok = NewRegion(10, 10, 0, 0).Intersect(NewRegion(10, 30, 0, 20))
+	assert.False(t, ok)
+	_, ok = NewRegion(10, 10, 0, 0).Intersect(NewRegion(30, 10, 20, 0))
+	assert.False(t, ok)
+
+	r, ok = NewRegion(10, -170, 0, 170).Intersect(NewRegion(10, -160, 0, 175))
+	assert.True(t, ok)
+	assert.Equal(t, NewRegion(10, -170, 0, 175), r)
+
+	r, ok = NewRegion(10, -170, 0, 170).Intersect(NewRegion(10, -160, 0, -175))
+	assert.True(t, ok)
+	assert.Equal(t, NewRegion(10, -170, 0, -175), r)
+
+	// two parts, the larger one is returned
+	r, ok = NewRegion(10, 100, 0, -100).Intersect(NewRegion(10, -90, 0, 50))
+	assert.True(t, ok)
+	assert.Equal(t, NewRegion(10, 100, 0, 50), r)
+}
+
+func TestRegionFromPoints(t *testing.T) {
+	assert.Equal(t, MapRegion{}, RegionFromPoints(nil))
+	assert.Equal(t, NewRegion(1, 2, 1, 2), RegionFromPoints([]Location{NewLocation(1, 2)}))
+	assert.Equal(t, NewRegion(10, 30, -5, -20), RegionFromPoints([]Location{NewLocation(10, 0), NewLocation(-5, 30), NewLocation(0, -20)}))
+
+	r := RegionFromPoints([]Location{NewLocation(0, 170), NewLocation(5, -170), NewLocation(-5, 179)})
+	assert.Equal(t, NewRegion(5, -170, -5, 170), r)
+	assert.True(t, r.Contains(NewLocation(0, 180)))
+}
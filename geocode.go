@@ -21,6 +21,9 @@ func (c *client) Geocode(ctx context.Context, query string, opts ...RequestOptio
 
 // ReverseGeocode returns a slice of addresses present at the specified location coordinates.
 func (c *client) ReverseGeocode(ctx context.Context, location Location, opts ...RequestOption) ([]Place, error) {
+	if err := location.Validate(); err != nil {
+		return nil, err
+	}
 	values := url.Values{}
 	values.Add("loc", location.String())
 	for _, opt := range opts {
@@ -0,0 +1,274 @@
+package geojson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jweckschmied/applemaps-go"
+)
+
+// addressFields contains the JSON field names of applemaps.StructuredAddress.
+var addressFields = jsonFields(reflect.TypeOf(applemaps.StructuredAddress{}))
+
+// FromPlace converts a place into a Point feature at its coordinate, with the display map region as bbox.
+// The fields of the place and of its structured address are the properties of the feature, omitting empty values.
+// Fields returned by the API that are not part of the library types are included as well.
+func FromPlace(p applemaps.Place) (*Feature, error) {
+	properties, err := objectOf(p)
+	if err != nil {
+		return nil, err
+	}
+	address, err := objectOf(p.StructuredAddress)
+	if err != nil {
+		return nil, err
+	}
+	delete(properties, "coordinate")
+	delete(properties, "displayMapRegion")
+	delete(properties, "structuredAddress")
+	for name, value := range address {
+		if _, ok := properties[name]; !ok {
+			properties[name] = value
+		}
+	}
+	return &Feature{BBox: bbox(p.DisplayMapRegion), Geometry: NewPoint(p.Coordinate), Properties: properties}, nil
+}
+
+// FromPlaces converts the places into a collection of Point features, see FromPlace.
+func FromPlaces(places []applemaps.Place) (*FeatureCollection, error) {
+	c := &FeatureCollection{Features: make([]*Feature, len(places))}
+	for i, p := range places {
+		f, err := FromPlace(p)
+		if err != nil {
+			return nil, fmt.Errorf("place %d: %w", i, err)
+		}
+		c.Features[i] = f
+	}
+	return c, nil
+}
+
+// FromSearchResponse converts the results of a search into a collection of Point features, see FromPlace.
+// The display map region of the response is the bbox of the collection.
+func FromSearchResponse(res *applemaps.SearchResponse) (*FeatureCollection, error) {
+	c, err := FromPlaces(res.Results)
+	if err != nil {
+		return nil, err
+	}
+	c.BBox = bbox(res.DisplayMapRegion)
+	return c, nil
+}
+
+// FromMapRegion converts a map region into a rectangular Polygon feature, with the region as bbox.
+// A region crossing the antimeridian is split into a MultiPolygon of the parts east and west of it,
+// as required by RFC 7946, while the bbox keeps its west longitude greater than its east longitude.
+func FromMapRegion(r applemaps.MapRegion) *Feature {
+	west, south, east, north := r.WestLongitude, r.SouthLatitude, r.EastLongitude, r.NorthLatitude
+	geometry := &Geometry{Type: PolygonType, Polygon: rectangle(west, south, east, north)}
+	if r.CrossesAntimeridian() {
+		geometry = &Geometry{Type: MultiPolygonType, MultiPolygon: [][][]Position{
+			rectangle(west, south, 180, north),
+			rectangle(-180, south, east, north),
+		}}
+	}
+	return &Feature{
+		BBox:       []float64{west, south, east, north},
+		Geometry:   geometry,
+		Properties: map[string]any{},
+	}
+}
+
+// FromDirections converts the routes of a directions response into a collection of LineString features along the
+// full path of each route. The name, distance, duration, tolls and transport type of the route are the properties
+// of the feature. It returns an *applemaps.IndexError if any index of the response is out of range.
+func FromDirections(res *applemaps.DirectionsResponse) (*FeatureCollection, error) {
+	c := &FeatureCollection{Features: make([]*Feature, len(res.Routes))}
+	for i, route := range res.Routes {
+		path, err := res.RoutePath(i)
+		if err != nil {
+			return nil, err
+		}
+		properties, err := objectOf(route)
+		if err != nil {
+			return nil, err
+		}
+		delete(properties, "stepIndexes")
+		c.Features[i] = &Feature{Geometry: NewLineString(path), Properties: properties}
+	}
+	return c, nil
+}
+
+// Place converts a Point feature created by FromPlace back into a place.
+// Properties that are neither fields of the place nor of its structured address are kept in Place.Extra.
+func (f *Feature) Place() (applemaps.Place, error) {
+	var p applemaps.Place
+	if f.Geometry == nil || f.Geometry.Type != PointType {
+		return p, fmt.Errorf("place must be a %s feature", PointType)
+	}
+	properties := make(map[string]any, len(f.Properties)+1)
+	address := make(map[string]any)
+	for name, value := range f.Properties {
+		if addressFields[name] {
+			address[name] = value
+		} else {
+			properties[name] = value
+		}
+	}
+	properties["structuredAddress"] = address
+	if err := fromObject(properties, &p); err != nil {
+		return p, err
+	}
+
+	region, err := region(f.BBox)
+	if err != nil {
+		return p, err
+	}
+	p.Coordinate = f.Geometry.Point.Location()
+	p.DisplayMapRegion = region
+	return p, nil
+}
+
+// MapRegion returns the region of the bbox of the feature, or of the extent of its Polygon or MultiPolygon geometry
+// if it has no bbox, such as a feature created by FromMapRegion. The extent of a MultiPolygon crosses the antimeridian
+// if the widest gap in longitude between its polygons is not the one across the antimeridian.
+func (f *Feature) MapRegion() (applemaps.MapRegion, error) {
+	if f.BBox != nil {
+		return region(f.BBox)
+	}
+	var polygons [][][]Position
+	if f.Geometry != nil && f.Geometry.Type == PolygonType {
+		polygons = [][][]Position{f.Geometry.Polygon}
+	} else if f.Geometry != nil && f.Geometry.Type == MultiPolygonType {
+		polygons = f.Geometry.MultiPolygon
+	}
+
+	var extents []applemaps.MapRegion
+	for _, polygon := range polygons {
+		if len(polygon) == 0 || len(polygon[0]) == 0 {
+			return applemaps.MapRegion{}, errors.New("polygon must have an exterior ring")
+		}
+		extents = append(extents, extent(polygon[0]))
+	}
+	if len(extents) == 0 {
+		return applemaps.MapRegion{}, fmt.Errorf("map region must be a %s or %s feature or have a bbox", PolygonType, MultiPolygonType)
+	}
+
+	sort.Slice(extents, func(i, j int) bool { return extents[i].WestLongitude < extents[j].WestLongitude })
+	r := extents[0]
+	gap, west, east := 0.0, 0.0, 0.0
+	for _, e := range extents[1:] {
+		if e.WestLongitude-r.EastLongitude > gap {
+			gap, west, east = e.WestLongitude-r.EastLongitude, e.WestLongitude, r.EastLongitude
+		}
+		r.NorthLatitude = math.Max(r.NorthLatitude, e.NorthLatitude)
+		r.SouthLatitude = math.Min(r.SouthLatitude, e.SouthLatitude)
+		r.EastLongitude = math.Max(r.EastLongitude, e.EastLongitude)
+	}
+	if gap > r.WestLongitude+360-r.EastLongitude {
+		r.WestLongitude, r.EastLongitude = west, east
+	}
+	return r, nil
+}
+
+// Route converts a LineString feature created by FromDirections back into a route and its path.
+// The step indexes of the route are not part of the feature, so they are empty.
+func (f *Feature) Route() (applemaps.Route, []applemaps.Location, error) {
+	var route applemaps.Route
+	if f.Geometry == nil || f.Geometry.Type != LineStringType {
+		return route, nil, fmt.Errorf("route must be a %s feature", LineStringType)
+	}
+	if err := fromObject(f.Properties, &route); err != nil {
+		return route, nil, err
+	}
+	path := make([]applemaps.Location, len(f.Geometry.LineString))
+	for i, p := range f.Geometry.LineString {
+		path[i] = p.Location()
+	}
+	return route, path, nil
+}
+
+// Places converts all features of a collection created by FromPlaces back into places, see Feature.Place.
+func (c *FeatureCollection) Places() ([]applemaps.Place, error) {
+	places := make([]applemaps.Place, len(c.Features))
+	for i, f := range c.Features {
+		p, err := f.Place()
+		if err != nil {
+			return nil, fmt.Errorf("feature %d: %w", i, err)
+		}
+		places[i] = p
+	}
+	return places, nil
+}
+
+// SearchResponse converts a collection created by FromSearchResponse back into a search response.
+// The pagination info of the original response is not part of the collection, so it is empty.
+func (c *FeatureCollection) SearchResponse() (*applemaps.SearchResponse, error) {
+	places, err := c.Places()
+	if err != nil {
+		return nil, err
+	}
+	region, err := region(c.BBox)
+	if err != nil {
+		return nil, err
+	}
+	return &applemaps.SearchResponse{DisplayMapRegion: region, Results: places}, nil
+}
+
+// rectangle returns the exterior ring of a rectangular polygon with the given bounds.
+func rectangle(west, south, east, north float64) [][]Position {
+	return [][]Position{{{west, south}, {east, south}, {east, north}, {west, north}, {west, south}}}
+}
+
+// extent returns the smallest region containing all positions of the ring, without crossing the antimeridian.
+func extent(ring []Position) applemaps.MapRegion {
+	r := applemaps.NewRegion(ring[0][1], ring[0][0], ring[0][1], ring[0][0])
+	for _, p := range ring[1:] {
+		r.NorthLatitude = math.Max(r.NorthLatitude, p[1])
+		r.SouthLatitude = math.Min(r.SouthLatitude, p[1])
+		r.EastLongitude = math.Max(r.EastLongitude, p[0])
+		r.WestLongitude = math.Min(r.WestLongitude, p[0])
+	}
+	return r
+}
+
+// objectOf encodes v as JSON and decodes it into a map, omitting null values and empty strings.
+func objectOf(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]any
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	for name, value := range object {
+		if value == nil || value == "" {
+			delete(object, name)
+		}
+	}
+	return object, nil
+}
+
+// fromObject decodes the map into v, by encoding it as JSON.
+func fromObject(object map[string]any, v any) error {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// jsonFields returns the JSON field names of the given struct type.
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+}
@@ -0,0 +1,198 @@
+// Package geojson converts the results of the Apple Maps Server API to and from GeoJSON (RFC 7946),
+// for rendering them with mapping libraries such as Leaflet or MapLibre.
+package geojson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jweckschmied/applemaps-go"
+)
+
+// Geometry types supported by Geometry.
+const (
+	PointType        = "Point"
+	LineStringType   = "LineString"
+	PolygonType      = "Polygon"
+	MultiPolygonType = "MultiPolygon"
+)
+
+// Position is a GeoJSON position, consisting of longitude and latitude in that order.
+// Altitudes of decoded positions are discarded.
+type Position [2]float64
+
+// Geometry is a Point, LineString, Polygon or MultiPolygon geometry. Only the coordinates matching the Type are encoded.
+type Geometry struct {
+	Type string
+	// Point is the position of a Point geometry.
+	Point Position
+	// LineString contains the positions of a LineString geometry.
+	LineString []Position
+	// Polygon contains the linear rings of a Polygon geometry, the first one being the exterior ring.
+	Polygon [][]Position
+	// MultiPolygon contains the polygons of a MultiPolygon geometry.
+	MultiPolygon [][][]Position
+}
+
+// Feature is a GeoJSON Feature.
+type Feature struct {
+	// BBox is the bounding box of the feature in the order west, south, east, north, if there is one.
+	BBox       []float64      `json:"bbox,omitempty"`
+	Geometry   *Geometry      `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+// FeatureCollection is a GeoJSON FeatureCollection.
+type FeatureCollection struct {
+	// BBox is the bounding box of the collection in the order west, south, east, north, if there is one.
+	BBox     []float64  `json:"bbox,omitempty"`
+	Features []*Feature `json:"features"`
+}
+
+// NewPoint creates a Point geometry at the given location.
+func NewPoint(l applemaps.Location) *Geometry {
+	return &Geometry{Type: PointType, Point: position(l)}
+}
+
+// NewLineString creates a LineString geometry along the given path.
+func NewLineString(path []applemaps.Location) *Geometry {
+	g := &Geometry{Type: LineStringType, LineString: make([]Position, len(path))}
+	for i, l := range path {
+		g.LineString[i] = position(l)
+	}
+	return g
+}
+
+// Location returns the location of the position.
+func (p Position) Location() applemaps.Location {
+	return applemaps.NewLocation(p[1], p[0])
+}
+
+func (g Geometry) MarshalJSON() ([]byte, error) {
+	var coordinates any
+	switch g.Type {
+	case PointType:
+		coordinates = g.Point
+	case LineStringType:
+		coordinates = nonNil(g.LineString)
+	case PolygonType:
+		coordinates = nonNil(g.Polygon)
+	case MultiPolygonType:
+		coordinates = nonNil(g.MultiPolygon)
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %q", g.Type)
+	}
+	return json.Marshal(struct {
+		Type        string `json:"type"`
+		Coordinates any    `json:"coordinates"`
+	}{g.Type, coordinates})
+}
+
+func (g *Geometry) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*g = Geometry{Type: raw.Type}
+	switch raw.Type {
+	case PointType:
+		return json.Unmarshal(raw.Coordinates, &g.Point)
+	case LineStringType:
+		return json.Unmarshal(raw.Coordinates, &g.LineString)
+	case PolygonType:
+		return json.Unmarshal(raw.Coordinates, &g.Polygon)
+	case MultiPolygonType:
+		return json.Unmarshal(raw.Coordinates, &g.MultiPolygon)
+	default:
+		return fmt.Errorf("unsupported geometry type %q", raw.Type)
+	}
+}
+
+func (f Feature) MarshalJSON() ([]byte, error) {
+	type feature Feature
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		feature
+	}{"Feature", feature(f)})
+}
+
+func (f *Feature) UnmarshalJSON(data []byte) error {
+	type feature Feature
+	var raw struct {
+		Type string `json:"type"`
+		feature
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Type != "Feature" {
+		return fmt.Errorf("unexpected type %q, expected Feature", raw.Type)
+	}
+	*f = Feature(raw.feature)
+	return nil
+}
+
+func (c FeatureCollection) MarshalJSON() ([]byte, error) {
+	type featureCollection FeatureCollection
+	c.Features = nonNil(c.Features)
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		featureCollection
+	}{"FeatureCollection", featureCollection(c)})
+}
+
+func (c *FeatureCollection) UnmarshalJSON(data []byte) error {
+	type featureCollection FeatureCollection
+	var raw struct {
+		Type string `json:"type"`
+		featureCollection
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Type != "FeatureCollection" {
+		return fmt.Errorf("unexpected type %q, expected FeatureCollection", raw.Type)
+	}
+	*c = FeatureCollection(raw.featureCollection)
+	return nil
+}
+
+// position returns the position of the location.
+func position(l applemaps.Location) Position {
+	return Position{l.Longitude, l.Latitude}
+}
+
+// bbox returns the bounding box of the region, or nil if the region is empty.
+func bbox(r applemaps.MapRegion) []float64 {
+	if r == (applemaps.MapRegion{}) {
+		return nil
+	}
+	return []float64{r.WestLongitude, r.SouthLatitude, r.EastLongitude, r.NorthLatitude}
+}
+
+// region returns the region of the bounding box, which can be two or three dimensional.
+// It returns an empty region if the bounding box is nil.
+func region(bbox []float64) (applemaps.MapRegion, error) {
+	switch len(bbox) {
+	case 0:
+		return applemaps.MapRegion{}, nil
+	case 4:
+		return applemaps.NewRegion(bbox[3], bbox[2], bbox[1], bbox[0]), nil
+	case 6:
+		return applemaps.NewRegion(bbox[4], bbox[3], bbox[1], bbox[0]), nil
+	default:
+		return applemaps.MapRegion{}, errors.New("bbox must contain 4 or 6 values")
+	}
+}
+
+// nonNil returns an empty slice if s is nil, so it is encoded as an empty JSON array.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
@@ -0,0 +1,165 @@
+package geojson
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jweckschmied/applemaps-go"
+	"github.com/stretchr/testify/assert"
+)
+
+const placeJSON = `{"id":"I7C250D2CDCB364A","alternateIds":["I1E0F3D2B3AC1A9C5"],"coordinate":{"latitude":51.0658585,"longitude":13.7466163},"displayMapRegion":{"southLatitude":51.0613669235794,"westLongitude":13.739468964416949,"northLatitude":51.070350076420596,"eastLongitude":13.75376363558305},"name":"Königsbrücker Straße 15","formattedAddressLines":["Königsbrücker Straße 15","01099 Dresden","Germany"],"structuredAddress":{"administrativeArea":"Saxony","locality":"Dresden","postCode":"01099","thoroughfare":"Königsbrücker Straße","subThoroughfare":"15","fullThoroughfare":"Königsbrücker Straße 15"},"country":"Germany","countryCode":"DE","rating":4.5}`
+
+func testPlace(t *testing.T) applemaps.Place {
+	var p applemaps.Place
+	assert.NoError(t, json.Unmarshal([]byte(placeJSON), &p))
+	return p
+}
+
+func TestFromPlace(t *testing.T) {
+	f, err := FromPlace(testPlace(t))
+	assert.NoError(t, err)
+	assert.Equal(t, NewPoint(applemaps.NewLocation(51.0658585, 13.7466163)), f.Geometry)
+	assert.Equal(t, []float64{13.739468964416949, 51.0613669235794, 13.75376363558305, 51.070350076420596}, f.BBox)
+	assert.Equal(t, "Königsbrücker Straße 15", f.Properties["name"])
+	assert.Equal(t, "Dresden", f.Properties["locality"])
+	assert.Equal(t, 4.5, f.Properties["rating"])
+	assert.NotContains(t, f.Properties, "administrativeAreaCode")
+	assert.NotContains(t, f.Properties, "coordinate")
+	assert.NotContains(t, f.Properties, "structuredAddress")
+
+	data, err := json.Marshal(f)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"type":"Feature"`)
+	assert.Contains(t, string(data), `"geometry":{"type":"Point","coordinates":[13.7466163,51.0658585]}`)
+}
+
+func TestPlace_RoundTrip(t *testing.T) {
+	expected := testPlace(t)
+	c, err := FromSearchResponse(&applemaps.SearchResponse{
+		DisplayMapRegion: applemaps.NewRegion(52, 14, 51, 13),
+		Results:          []applemaps.Place{expected, {Name: "Empty"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{13, 51, 14, 52}, c.BBox)
+
+	data, err := json.Marshal(c)
+	assert.NoError(t, err)
+	var decoded FeatureCollection
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+
+	res, err := decoded.SearchResponse()
+	assert.NoError(t, err)
+	assert.Equal(t, applemaps.NewRegion(52, 14, 51, 13), res.DisplayMapRegion)
+	assert.Len(t, res.Results, 2)
+	assert.Equal(t, expected, res.Results[0])
+	assert.Equal(t, "Empty", res.Results[1].Name)
+	assert.Equal(t, applemaps.MapRegion{}, res.Results[1].DisplayMapRegion)
+}
+
+func TestMapRegion_RoundTrip(t *testing.T) {
+	r := applemaps.NewRegion(51.07, 13.75, 51.06, 13.73)
+	f := FromMapRegion(r)
+	data, err := json.Marshal(f)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"Feature","bbox":[13.73,51.06,13.75,51.07],"properties":{},"geometry":{"type":"Polygon","coordinates":[[[13.73,51.06],[13.75,51.06],[13.75,51.07],[13.73,51.07],[13.73,51.06]]]}}`, string(data))
+
+	var decoded Feature
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	region, err := decoded.MapRegion()
+	assert.NoError(t, err)
+	assert.Equal(t, r, region)
+
+	decoded.BBox = nil
+	region, err = decoded.MapRegion()
+	assert.NoError(t, err)
+	assert.Equal(t, r, region)
+}
+
+func TestMapRegion_RoundTrip_Antimeridian(t *testing.T) {
+	r := applemaps.NewRegion(-16, -178, -19, 177)
+	f := FromMapRegion(r)
+	data, err := json.Marshal(f)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"Feature","bbox":[177,-19,-178,-16],"properties":{},"geometry":{"type":"MultiPolygon","coordinates":[`+
+		`[[[177,-19],[180,-19],[180,-16],[177,-16],[177,-19]]],`+
+		`[[[-180,-19],[-178,-19],[-178,-16],[-180,-16],[-180,-19]]]]}}`, string(data))
+
+	var decoded Feature
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	region, err := decoded.MapRegion()
+	assert.NoError(t, err)
+	assert.Equal(t, r, region)
+	assert.True(t, region.CrossesAntimeridian())
+
+	decoded.BBox = nil
+	region, err = decoded.MapRegion()
+	assert.NoError(t, err)
+	assert.Equal(t, r, region)
+
+	// polygons separated by a gap narrower than the one across the antimeridian do not cross it
+	decoded.Geometry.MultiPolygon[1] = rectangle(10, -20, 20, -15)
+	region, err = decoded.MapRegion()
+	assert.NoError(t, err)
+	assert.Equal(t, applemaps.NewRegion(-15, 180, -20, 10), region)
+}
+
+func TestDirections_RoundTrip(t *testing.T) {
+	res := &applemaps.DirectionsResponse{
+		Routes: []applemaps.Route{{Name: "Prager Straße", DistanceMeters: 317, DurationSeconds: 149, HasTolls: true, StepIndexes: []int{0, 1}, TransportType: "Automobile"}},
+		Steps:  []applemaps.Step{{StepPathIndex: 0}, {StepPathIndex: 1}},
+		StepPaths: [][]applemaps.Location{
+			{applemaps.NewLocation(51.042699, 13.735173), applemaps.NewLocation(51.042634, 13.735153)},
+			{applemaps.NewLocation(51.042634, 13.735153), applemaps.NewLocation(51.04141, 13.734339)},
+		},
+	}
+	c, err := FromDirections(res)
+	assert.NoError(t, err)
+	assert.Len(t, c.Features, 1)
+	assert.Equal(t, map[string]any{"name": "Prager Straße", "distanceMeters": 317.0, "durationSeconds": 149.0, "hasTolls": true, "transportType": "Automobile"}, c.Features[0].Properties)
+
+	data, err := json.Marshal(c)
+	assert.NoError(t, err)
+	var decoded FeatureCollection
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	route, path, err := decoded.Features[0].Route()
+	assert.NoError(t, err)
+	expected := res.Routes[0]
+	expected.StepIndexes = nil
+	assert.Equal(t, expected, route)
+	assert.Equal(t, []applemaps.Location{
+		applemaps.NewLocation(51.042699, 13.735173), applemaps.NewLocation(51.042634, 13.735153), applemaps.NewLocation(51.04141, 13.734339),
+	}, path)
+
+	res.Steps[1].StepPathIndex = 5
+	_, err = FromDirections(res)
+	assert.Error(t, err)
+}
+
+func TestUnmarshal_Invalid(t *testing.T) {
+	var f Feature
+	assert.Error(t, json.Unmarshal([]byte(`{"type":"FeatureCollection","features":[]}`), &f))
+	assert.Error(t, json.Unmarshal([]byte(`{"type":"Feature","geometry":{"type":"MultiPoint","coordinates":[]}}`), &f))
+
+	var c FeatureCollection
+	assert.Error(t, json.Unmarshal([]byte(`{"type":"Feature"}`), &c))
+	assert.NoError(t, json.Unmarshal([]byte(`{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Point","coordinates":[13.7,51.0,120]},"properties":null}]}`), &c))
+	assert.Equal(t, Position{13.7, 51.0}, c.Features[0].Geometry.Point)
+
+	_, err := (&Feature{Geometry: NewLineString(nil)}).Place()
+	assert.Error(t, err)
+	_, _, err = (&Feature{Geometry: NewPoint(applemaps.Location{})}).Route()
+	assert.Error(t, err)
+	_, err = (&Feature{BBox: []float64{1, 2, 3}}).MapRegion()
+	assert.Error(t, err)
+}
+
+func TestMarshal_Empty(t *testing.T) {
+	data, err := json.Marshal(FeatureCollection{})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"FeatureCollection","features":[]}`, string(data))
+
+	data, err = json.Marshal(NewLineString(nil))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"LineString","coordinates":[]}`, string(data))
+}
@@ -0,0 +1,134 @@
+// Package gpx writes routes and places returned by the Apple Maps Server API as GPX 1.1 documents,
+// which can be loaded into GPS devices such as those made by Garmin.
+package gpx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jweckschmied/applemaps-go"
+)
+
+// Namespace is the XML namespace of GPX 1.1 documents.
+const Namespace = "http://www.topografix.com/GPX/1/1"
+
+// creator is the value of the creator attribute of written documents.
+const creator = "applemaps-go"
+
+type point struct {
+	Lat  string `xml:"lat,attr"`
+	Lon  string `xml:"lon,attr"`
+	Name string `xml:"name,omitempty"`
+	Desc string `xml:"desc,omitempty"`
+}
+
+type route struct {
+	XMLName xml.Name `xml:"rte"`
+	Name    string   `xml:"name,omitempty"`
+	Desc    string   `xml:"desc,omitempty"`
+	Points  []point  `xml:"rtept"`
+}
+
+type track struct {
+	XMLName xml.Name `xml:"trk"`
+	Name    string   `xml:"name,omitempty"`
+	Segment struct {
+		Points []point `xml:"trkpt"`
+	} `xml:"trkseg"`
+}
+
+// WriteRoute writes the route with index i of the directions response to w as a GPX document.
+// The document contains a route with a route point at the start of every step, named after the instructions
+// of the step, and a track along the full path of the route.
+func WriteRoute(w io.Writer, res *applemaps.DirectionsResponse, i int) error {
+	steps, err := res.RouteSteps(i)
+	if err != nil {
+		return err
+	}
+	path, err := res.RoutePath(i)
+	if err != nil {
+		return err
+	}
+
+	r := res.Routes[i]
+	rte := route{Name: r.Name, Desc: describeRoute(r)}
+	for _, step := range steps {
+		if stepPath := res.StepPaths[step.StepPathIndex]; len(stepPath) > 0 {
+			rte.Points = append(rte.Points, newPoint(stepPath[0], step.Instructions, ""))
+		}
+	}
+	trk := track{Name: r.Name}
+	for _, l := range path {
+		trk.Segment.Points = append(trk.Segment.Points, newPoint(l, "", ""))
+	}
+
+	return write(w, func(e *xml.Encoder) error {
+		if err := e.Encode(rte); err != nil {
+			return err
+		}
+		return e.Encode(trk)
+	})
+}
+
+// WritePlaces writes the places to w as a GPX document, containing a waypoint at the coordinate of every place,
+// named after the place and described by its formatted address.
+func WritePlaces(w io.Writer, places []applemaps.Place) error {
+	return write(w, func(e *xml.Encoder) error {
+		for _, p := range places {
+			wpt := newPoint(p.Coordinate, p.Name, strings.Join(p.FormattedAddressLines, ", "))
+			if err := e.EncodeElement(wpt, xml.StartElement{Name: xml.Name{Local: "wpt"}}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// write writes a GPX document to w, with the content written by body.
+func write(w io.Writer, body func(e *xml.Encoder) error) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	gpx := xml.StartElement{
+		Name: xml.Name{Local: "gpx"},
+		Attr: []xml.Attr{
+			{Name: xml.Name{Local: "version"}, Value: "1.1"},
+			{Name: xml.Name{Local: "creator"}, Value: creator},
+			{Name: xml.Name{Local: "xmlns"}, Value: Namespace},
+		},
+	}
+	if err := e.EncodeToken(gpx); err != nil {
+		return err
+	}
+	if err := body(e); err != nil {
+		return err
+	}
+	if err := e.EncodeToken(gpx.End()); err != nil {
+		return err
+	}
+	if err := e.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func newPoint(l applemaps.Location, name, desc string) point {
+	return point{
+		Lat:  strconv.FormatFloat(l.Latitude, 'f', -1, 64),
+		Lon:  strconv.FormatFloat(l.Longitude, 'f', -1, 64),
+		Name: name,
+		Desc: desc,
+	}
+}
+
+// describeRoute returns a description of the distance and duration of the route.
+func describeRoute(r applemaps.Route) string {
+	return fmt.Sprintf("%d m, %s", r.DistanceMeters, time.Duration(r.DurationSeconds)*time.Second)
+}
@@ -0,0 +1,138 @@
+package gpx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jweckschmied/applemaps-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// document mirrors the structure of the GPX 1.1 schema for the elements written by this package.
+type document struct {
+	XMLName   xml.Name `xml:"http://www.topografix.com/GPX/1/1 gpx"`
+	Version   string   `xml:"version,attr"`
+	Creator   string   `xml:"creator,attr"`
+	Waypoints []struct {
+		Lat  float64 `xml:"lat,attr"`
+		Lon  float64 `xml:"lon,attr"`
+		Name string  `xml:"http://www.topografix.com/GPX/1/1 name"`
+		Desc string  `xml:"http://www.topografix.com/GPX/1/1 desc"`
+	} `xml:"http://www.topografix.com/GPX/1/1 wpt"`
+	Routes []struct {
+		Name   string `xml:"http://www.topografix.com/GPX/1/1 name"`
+		Desc   string `xml:"http://www.topografix.com/GPX/1/1 desc"`
+		Points []struct {
+			Lat  float64 `xml:"lat,attr"`
+			Lon  float64 `xml:"lon,attr"`
+			Name string  `xml:"http://www.topografix.com/GPX/1/1 name"`
+		} `xml:"http://www.topografix.com/GPX/1/1 rtept"`
+	} `xml:"http://www.topografix.com/GPX/1/1 rte"`
+	Tracks []struct {
+		Name     string `xml:"http://www.topografix.com/GPX/1/1 name"`
+		Segments []struct {
+			Points []struct {
+				Lat float64 `xml:"lat,attr"`
+				Lon float64 `xml:"lon,attr"`
+			} `xml:"http://www.topografix.com/GPX/1/1 trkpt"`
+		} `xml:"http://www.topografix.com/GPX/1/1 trkseg"`
+	} `xml:"http://www.topografix.com/GPX/1/1 trk"`
+}
+
+// childOrder lists the order of the GPX elements wpt, rte and trk, which the schema requires to appear in this order.
+var childOrder = map[string]int{"wpt": 0, "rte": 1, "trk": 2}
+
+// decode decodes the GPX document and checks the order of the children of the root element.
+func decode(t *testing.T, data []byte) document {
+	assert.True(t, strings.HasPrefix(string(data), xml.Header))
+
+	d := xml.NewDecoder(bytes.NewReader(data))
+	depth, last := 0, 0
+	for {
+		token, err := d.Token()
+		if err != nil {
+			break
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 2 {
+				order := childOrder[token.Name.Local]
+				assert.GreaterOrEqual(t, order, last, "element %s out of order", token.Name.Local)
+				last = order
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+
+	var doc document
+	assert.NoError(t, xml.Unmarshal(data, &doc))
+	assert.Equal(t, "1.1", doc.Version)
+	assert.NotEmpty(t, doc.Creator)
+	return doc
+}
+
+func testDirections() *applemaps.DirectionsResponse {
+	return &applemaps.DirectionsResponse{
+		Routes: []applemaps.Route{{Name: "Prager Straße & More", DistanceMeters: 317, DurationSeconds: 149, StepIndexes: []int{0, 1}}},
+		Steps: []applemaps.Step{
+			{StepPathIndex: 0},
+			{StepPathIndex: 1, Instructions: "Turn right onto St Petersburger Straße"},
+		},
+		StepPaths: [][]applemaps.Location{
+			{applemaps.NewLocation(51.042699, 13.735173), applemaps.NewLocation(51.042634, 13.735153)},
+			{applemaps.NewLocation(51.042634, 13.735153), applemaps.NewLocation(51.04141, 13.734339)},
+		},
+	}
+}
+
+func TestWriteRoute(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, WriteRoute(&buf, testDirections(), 0))
+	doc := decode(t, buf.Bytes())
+
+	assert.Empty(t, doc.Waypoints)
+	assert.Len(t, doc.Routes, 1)
+	assert.Equal(t, "Prager Straße & More", doc.Routes[0].Name)
+	assert.Equal(t, "317 m, 2m29s", doc.Routes[0].Desc)
+	assert.Len(t, doc.Routes[0].Points, 2)
+	assert.Equal(t, 51.042699, doc.Routes[0].Points[0].Lat)
+	assert.Equal(t, 13.735173, doc.Routes[0].Points[0].Lon)
+	assert.Equal(t, "Turn right onto St Petersburger Straße", doc.Routes[0].Points[1].Name)
+
+	assert.Len(t, doc.Tracks, 1)
+	assert.Len(t, doc.Tracks[0].Segments, 1)
+	assert.Len(t, doc.Tracks[0].Segments[0].Points, 3)
+	assert.Equal(t, 51.04141, doc.Tracks[0].Segments[0].Points[2].Lat)
+}
+
+func TestWriteRoute_Invalid(t *testing.T) {
+	var buf bytes.Buffer
+	var indexErr *applemaps.IndexError
+	err := WriteRoute(&buf, testDirections(), 1)
+	assert.True(t, errors.As(err, &indexErr))
+	assert.Zero(t, buf.Len())
+}
+
+func TestWritePlaces(t *testing.T) {
+	places := []applemaps.Place{
+		{Name: "Königsbrücker Straße 15", FormattedAddressLines: []string{"Königsbrücker Straße 15", "01099 Dresden"}, Coordinate: applemaps.NewLocation(51.0658585, 13.7466163)},
+		{Coordinate: applemaps.NewLocation(-33.856784, 151.215297)},
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, WritePlaces(&buf, places))
+	doc := decode(t, buf.Bytes())
+
+	assert.Len(t, doc.Waypoints, 2)
+	assert.Equal(t, 51.0658585, doc.Waypoints[0].Lat)
+	assert.Equal(t, 13.7466163, doc.Waypoints[0].Lon)
+	assert.Equal(t, "Königsbrücker Straße 15", doc.Waypoints[0].Name)
+	assert.Equal(t, "Königsbrücker Straße 15, 01099 Dresden", doc.Waypoints[0].Desc)
+	assert.Equal(t, -33.856784, doc.Waypoints[1].Lat)
+	assert.NotContains(t, buf.String(), "<name></name>")
+	assert.Empty(t, doc.Routes)
+}
@@ -0,0 +1,140 @@
+// Package kml writes routes and places returned by the Apple Maps Server API as KML 2.2 documents,
+// which can be opened in Google Earth and other GIS applications.
+package kml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jweckschmied/applemaps-go"
+)
+
+// Namespace is the XML namespace of KML 2.2 documents.
+const Namespace = "http://www.opengis.net/kml/2.2"
+
+type placemark struct {
+	XMLName     xml.Name    `xml:"Placemark"`
+	Name        string      `xml:"name,omitempty"`
+	Description string      `xml:"description,omitempty"`
+	Point       *point      `xml:"Point"`
+	LineString  *lineString `xml:"LineString"`
+}
+
+type point struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type lineString struct {
+	Tessellate  int    `xml:"tessellate"`
+	Coordinates string `xml:"coordinates"`
+}
+
+type folder struct {
+	XMLName    xml.Name    `xml:"Folder"`
+	Name       string      `xml:"name"`
+	Placemarks []placemark `xml:"Placemark"`
+}
+
+// WriteRoute writes the route with index i of the directions response to w as a KML document.
+// The document contains a placemark with a line string along the full path of the route,
+// and a folder with a point placemark at the start of every step, named after the instructions of the step.
+func WriteRoute(w io.Writer, res *applemaps.DirectionsResponse, i int) error {
+	steps, err := res.RouteSteps(i)
+	if err != nil {
+		return err
+	}
+	path, err := res.RoutePath(i)
+	if err != nil {
+		return err
+	}
+
+	r := res.Routes[i]
+	line := placemark{
+		Name:        r.Name,
+		Description: fmt.Sprintf("%d m, %s", r.DistanceMeters, time.Duration(r.DurationSeconds)*time.Second),
+		LineString:  &lineString{Tessellate: 1, Coordinates: coordinates(path...)},
+	}
+	stepFolder := folder{Name: "Steps"}
+	for _, step := range steps {
+		if stepPath := res.StepPaths[step.StepPathIndex]; len(stepPath) > 0 {
+			stepFolder.Placemarks = append(stepFolder.Placemarks, placemark{
+				Name:  step.Instructions,
+				Point: &point{Coordinates: coordinates(stepPath[0])},
+			})
+		}
+	}
+
+	return write(w, r.Name, func(e *xml.Encoder) error {
+		if err := e.Encode(line); err != nil {
+			return err
+		}
+		return e.Encode(stepFolder)
+	})
+}
+
+// WritePlaces writes the places to w as a KML document, containing a point placemark at the coordinate
+// of every place, named after the place and described by its formatted address.
+func WritePlaces(w io.Writer, places []applemaps.Place) error {
+	return write(w, "", func(e *xml.Encoder) error {
+		for _, p := range places {
+			err := e.Encode(placemark{
+				Name:        p.Name,
+				Description: strings.Join(p.FormattedAddressLines, ", "),
+				Point:       &point{Coordinates: coordinates(p.Coordinate)},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// write writes a KML document with the given name to w, with the content of the document written by body.
+func write(w io.Writer, name string, body func(e *xml.Encoder) error) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	kml := xml.StartElement{Name: xml.Name{Local: "kml"}, Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: Namespace}}}
+	document := xml.StartElement{Name: xml.Name{Local: "Document"}}
+	if err := e.EncodeToken(kml); err != nil {
+		return err
+	}
+	if err := e.EncodeToken(document); err != nil {
+		return err
+	}
+	if name != "" {
+		if err := e.EncodeElement(name, xml.StartElement{Name: xml.Name{Local: "name"}}); err != nil {
+			return err
+		}
+	}
+	if err := body(e); err != nil {
+		return err
+	}
+	if err := e.EncodeToken(document.End()); err != nil {
+		return err
+	}
+	if err := e.EncodeToken(kml.End()); err != nil {
+		return err
+	}
+	if err := e.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// coordinates returns the locations as KML coordinate tuples of longitude and latitude, separated by spaces.
+func coordinates(locations ...applemaps.Location) string {
+	tuples := make([]string, len(locations))
+	for i, l := range locations {
+		tuples[i] = strconv.FormatFloat(l.Longitude, 'f', -1, 64) + "," + strconv.FormatFloat(l.Latitude, 'f', -1, 64)
+	}
+	return strings.Join(tuples, " ")
+}
@@ -0,0 +1,132 @@
+package kml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jweckschmied/applemaps-go"
+	"github.com/stretchr/testify/assert"
+)
+
+type decodedPlacemark struct {
+	Name        string `xml:"http://www.opengis.net/kml/2.2 name"`
+	Description string `xml:"http://www.opengis.net/kml/2.2 description"`
+	Point       *struct {
+		Coordinates string `xml:"http://www.opengis.net/kml/2.2 coordinates"`
+	} `xml:"http://www.opengis.net/kml/2.2 Point"`
+	LineString *struct {
+		Tessellate  int    `xml:"http://www.opengis.net/kml/2.2 tessellate"`
+		Coordinates string `xml:"http://www.opengis.net/kml/2.2 coordinates"`
+	} `xml:"http://www.opengis.net/kml/2.2 LineString"`
+}
+
+// document mirrors the structure of the KML 2.2 schema for the elements written by this package.
+type document struct {
+	XMLName  xml.Name `xml:"http://www.opengis.net/kml/2.2 kml"`
+	Document struct {
+		Name       string             `xml:"http://www.opengis.net/kml/2.2 name"`
+		Placemarks []decodedPlacemark `xml:"http://www.opengis.net/kml/2.2 Placemark"`
+		Folders    []struct {
+			Name       string             `xml:"http://www.opengis.net/kml/2.2 name"`
+			Placemarks []decodedPlacemark `xml:"http://www.opengis.net/kml/2.2 Placemark"`
+		} `xml:"http://www.opengis.net/kml/2.2 Folder"`
+	} `xml:"http://www.opengis.net/kml/2.2 Document"`
+}
+
+// elementOrder lists the order in which the schema requires the child elements of features and geometries to appear.
+var elementOrder = map[string]int{"name": 0, "description": 1, "tessellate": 2, "Point": 3, "LineString": 3, "coordinates": 4, "Placemark": 5, "Folder": 5}
+
+// decode decodes the KML document and checks the order of the child elements of every element.
+func decode(t *testing.T, data []byte) document {
+	assert.True(t, strings.HasPrefix(string(data), xml.Header))
+
+	d := xml.NewDecoder(bytes.NewReader(data))
+	last := []int{0}
+	for {
+		token, err := d.Token()
+		if err != nil {
+			break
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			order := elementOrder[token.Name.Local]
+			assert.GreaterOrEqual(t, order, last[len(last)-1], "element %s out of order", token.Name.Local)
+			last[len(last)-1] = order
+			last = append(last, 0)
+		case xml.EndElement:
+			last = last[:len(last)-1]
+		}
+	}
+
+	var doc document
+	assert.NoError(t, xml.Unmarshal(data, &doc))
+	return doc
+}
+
+func testDirections() *applemaps.DirectionsResponse {
+	return &applemaps.DirectionsResponse{
+		Routes: []applemaps.Route{{Name: "Prager Straße", DistanceMeters: 317, DurationSeconds: 149, StepIndexes: []int{0, 1}}},
+		Steps: []applemaps.Step{
+			{StepPathIndex: 0},
+			{StepPathIndex: 1, Instructions: "Turn right onto St Petersburger Straße"},
+		},
+		StepPaths: [][]applemaps.Location{
+			{applemaps.NewLocation(51.042699, 13.735173), applemaps.NewLocation(51.042634, 13.735153)},
+			{applemaps.NewLocation(51.042634, 13.735153), applemaps.NewLocation(51.04141, 13.734339)},
+		},
+	}
+}
+
+func TestWriteRoute(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, WriteRoute(&buf, testDirections(), 0))
+	doc := decode(t, buf.Bytes())
+
+	assert.Equal(t, "Prager Straße", doc.Document.Name)
+	assert.Len(t, doc.Document.Placemarks, 1)
+	route := doc.Document.Placemarks[0]
+	assert.Equal(t, "Prager Straße", route.Name)
+	assert.Equal(t, "317 m, 2m29s", route.Description)
+	assert.Nil(t, route.Point)
+	assert.Equal(t, 1, route.LineString.Tessellate)
+	assert.Equal(t, "13.735173,51.042699 13.735153,51.042634 13.734339,51.04141", route.LineString.Coordinates)
+
+	assert.Len(t, doc.Document.Folders, 1)
+	steps := doc.Document.Folders[0].Placemarks
+	assert.Len(t, steps, 2)
+	assert.Equal(t, "13.735173,51.042699", steps[0].Point.Coordinates)
+	assert.Equal(t, "Turn right onto St Petersburger Straße", steps[1].Name)
+	assert.Equal(t, "13.735153,51.042634", steps[1].Point.Coordinates)
+}
+
+func TestWriteRoute_Invalid(t *testing.T) {
+	res := testDirections()
+	res.Steps[1].StepPathIndex = 2
+	var buf bytes.Buffer
+	var indexErr *applemaps.IndexError
+	err := WriteRoute(&buf, res, 0)
+	assert.True(t, errors.As(err, &indexErr))
+	assert.Zero(t, buf.Len())
+}
+
+func TestWritePlaces(t *testing.T) {
+	places := []applemaps.Place{
+		{Name: "Königsbrücker Straße 15", FormattedAddressLines: []string{"Königsbrücker Straße 15", "01099 Dresden"}, Coordinate: applemaps.NewLocation(51.0658585, 13.7466163)},
+		{Name: "Sydney Opera House", Coordinate: applemaps.NewLocation(-33.856784, 151.215297)},
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, WritePlaces(&buf, places))
+	doc := decode(t, buf.Bytes())
+
+	assert.Empty(t, doc.Document.Name)
+	assert.Empty(t, doc.Document.Folders)
+	assert.Len(t, doc.Document.Placemarks, 2)
+	assert.Equal(t, "Königsbrücker Straße 15", doc.Document.Placemarks[0].Name)
+	assert.Equal(t, "Königsbrücker Straße 15, 01099 Dresden", doc.Document.Placemarks[0].Description)
+	assert.Equal(t, "13.7466163,51.0658585", doc.Document.Placemarks[0].Point.Coordinates)
+	assert.Equal(t, "151.215297,-33.856784", doc.Document.Placemarks[1].Point.Coordinates)
+	assert.Nil(t, doc.Document.Placemarks[1].LineString)
+}
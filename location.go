@@ -1,6 +1,7 @@
 package applemaps
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 )
@@ -19,6 +20,10 @@ type (
 	}
 
 	Place struct {
+		// ID is the identifier of the place, which can be used to look up the place again using Client.Place().
+		ID string `json:"id"`
+		// AlternateIDs are other identifiers of the same place.
+		AlternateIDs          []string          `json:"alternateIds"`
 		Country               string            `json:"country"`
 		CountryCode           string            `json:"countryCode"`
 		DisplayMapRegion      MapRegion         `json:"displayMapRegion"`
@@ -26,10 +31,16 @@ type (
 		Name                  string            `json:"name"`
 		Coordinate            Location          `json:"coordinate"`
 		StructuredAddress     StructuredAddress `json:"structuredAddress"`
+		// PoiCategory is the category of the place if it is a point of interest.
+		PoiCategory Category `json:"poiCategory"`
+		// Extra contains the fields returned by the API that are not part of the Place type, keyed by their name.
+		Extra map[string]json.RawMessage `json:"-"`
 	}
 
 	StructuredAddress struct {
-		AdministrativeArea     string   `json:"administrativeArea"`
+		// AdministrativeArea The state or province of the place.
+		AdministrativeArea string `json:"administrativeArea"`
+		// AdministrativeAreaCode The short code for the state or area.
 		AdministrativeAreaCode string   `json:"administrativeAreaCode"`
 		AreasOfInterest        []string `json:"areasOfInterest"`
 		DependentLocalities    []string `json:"dependentLocalities"`
@@ -37,10 +48,12 @@ type (
 		Locality               string   `json:"locality"`
 		PostCode               string   `json:"postCode"`
 		SubLocality            string   `json:"subLocality"`
-		// SubThoroughfare The short code for the state or area.
+		// SubThoroughfare The number on the street at the address.
 		SubThoroughfare string `json:"subThoroughfare"`
-		// Thoroughfare The state or province of the place
+		// Thoroughfare The street name at the address.
 		Thoroughfare string `json:"thoroughfare"`
+		// Extra contains the fields returned by the API that are not part of the StructuredAddress type, keyed by their name.
+		Extra map[string]json.RawMessage `json:"-"`
 	}
 )
 
@@ -75,3 +88,47 @@ func (r MapRegion) String() string {
 		strconv.FormatFloat(r.WestLongitude, 'f', -1, 64),
 	)
 }
+
+// UnmarshalJSON decodes a place, keeping fields unknown to the Place type in Extra.
+func (p *Place) UnmarshalJSON(data []byte) error {
+	type place Place
+	var v place
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	extra, err := unknownFields[place](data)
+	if err != nil {
+		return err
+	}
+	v.Extra = extra
+	*p = Place(v)
+	return nil
+}
+
+// MarshalJSON encodes a place, including the fields in Extra.
+func (p Place) MarshalJSON() ([]byte, error) {
+	type place Place
+	return marshalWithExtra(place(p), p.Extra)
+}
+
+// UnmarshalJSON decodes a structured address, keeping fields unknown to the StructuredAddress type in Extra.
+func (a *StructuredAddress) UnmarshalJSON(data []byte) error {
+	type structuredAddress StructuredAddress
+	var v structuredAddress
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	extra, err := unknownFields[structuredAddress](data)
+	if err != nil {
+		return err
+	}
+	v.Extra = extra
+	*a = StructuredAddress(v)
+	return nil
+}
+
+// MarshalJSON encodes a structured address, including the fields in Extra.
+func (a StructuredAddress) MarshalJSON() ([]byte, error) {
+	type structuredAddress StructuredAddress
+	return marshalWithExtra(structuredAddress(a), a.Extra)
+}
@@ -0,0 +1,43 @@
+package applemaps
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	place_ExtraFieldsResponse string = `{"id":"I7C250D2CDCB364A","name":"Coffeeplace","poiCategory":"Cafe","coordinate":{"latitude":51.0658585,"longitude":13.7466163},"structuredAddress":{"locality":"Dresden","neighborhood":"Neustadt"},"country":"Germany","countryCode":"DE","rating":4.5,"urls":["https://example.com"]}`
+)
+
+func TestPlace_UnmarshalJSON(t *testing.T) {
+	var place Place
+	assert.NoError(t, json.Unmarshal([]byte(place_ExtraFieldsResponse), &place))
+	assert.Equal(t, "I7C250D2CDCB364A", place.ID)
+	assert.Equal(t, Cafe, place.PoiCategory)
+	assert.Equal(t, "Dresden", place.StructuredAddress.Locality)
+	assert.Equal(t, map[string]json.RawMessage{
+		"rating": json.RawMessage(`4.5`),
+		"urls":   json.RawMessage(`["https://example.com"]`),
+	}, place.Extra)
+	assert.Equal(t, map[string]json.RawMessage{"neighborhood": json.RawMessage(`"Neustadt"`)}, place.StructuredAddress.Extra)
+}
+
+func TestPlace_UnmarshalJSON_NoExtraFields(t *testing.T) {
+	var res SearchResponse
+	assert.NoError(t, json.Unmarshal([]byte(geocode_SuccessResponse), &res))
+	assert.Nil(t, res.Results[0].Extra)
+	assert.Nil(t, res.Results[0].StructuredAddress.Extra)
+}
+
+func TestPlace_MarshalJSON(t *testing.T) {
+	var place Place
+	assert.NoError(t, json.Unmarshal([]byte(place_ExtraFieldsResponse), &place))
+	data, err := json.Marshal(place)
+	assert.NoError(t, err)
+
+	var roundTrip Place
+	assert.NoError(t, json.Unmarshal(data, &roundTrip))
+	assert.Equal(t, place, roundTrip)
+}
@@ -0,0 +1,132 @@
+package applemaps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// redacted replaces sensitive values in log records.
+const redacted = "[REDACTED]"
+
+// sensitiveParameters lists substrings of query parameter names whose values are redacted in log records.
+var sensitiveParameters = []string{"token", "key", "secret", "signature"}
+
+// Logger is the interface used by the client to log requests, using key-value pairs as arguments.
+// It is satisfied by *slog.Logger.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// WithLogger returns a functional ClientOption used to log all API calls
+// when creating a new Apple Maps API Client using NewAppleMaps().
+// Every attempt of a request is logged with its endpoint, query parameters, headers, latency, status code and
+// the number of bytes read, at debug level if it succeeded and at warn level otherwise.
+// Access token renewals and retries are logged at info level. The value of the Authorization header is never logged.
+func WithLogger(logger Logger) ClientOption {
+	return func(c *client) {
+		c.logger = logger
+	}
+}
+
+// WithVerboseLogging returns a functional ClientOption used to additionally log the body of every response at debug level.
+// The access token in responses of the token endpoint is redacted.
+// It has no effect unless a Logger is set using WithLogger().
+func WithVerboseLogging() ClientOption {
+	return func(c *client) {
+		c.verbose = true
+	}
+}
+
+// logRequests is a Middleware that logs every request attempt to the client's Logger.
+func (c *client) logRequests(next Handler) Handler {
+	return func(ctx context.Context, req *Request) (*Response, error) {
+		start := time.Now()
+		res, err := next(ctx, req)
+		latency := time.Since(start)
+
+		args := []any{
+			"endpoint", req.Endpoint,
+			"path", req.Path,
+			"attempt", req.Attempt,
+			"query", sanitizeValues(req.Values),
+			"header", sanitizeHeader(req.Header),
+			"latency", latency,
+		}
+		var body []byte
+		if res != nil {
+			if res.Body != nil {
+				var readErr error
+				body, readErr = io.ReadAll(res.Body)
+				res.Body = bytes.NewReader(body)
+				if readErr != nil && err == nil {
+					err = readErr
+				}
+			}
+			args = append(args, "status", res.StatusCode, "bytes", len(body))
+		}
+
+		if err != nil {
+			c.logger.Warn("apple maps request failed", append(args, "error", err)...)
+		} else {
+			c.logger.Debug("apple maps request", args...)
+		}
+		if c.verbose && res != nil {
+			c.logger.Debug("apple maps response body", "endpoint", req.Endpoint, "attempt", req.Attempt, "body", sanitizeBody(req.Endpoint, body))
+		}
+		return res, err
+	}
+}
+
+// sanitizeValues returns the encoded query parameters, with the values of sensitive parameters redacted.
+func sanitizeValues(values url.Values) string {
+	sanitized := make(url.Values, len(values))
+	for key, v := range values {
+		sanitized[key] = v
+		name := strings.ToLower(key)
+		for _, s := range sensitiveParameters {
+			if strings.Contains(name, s) {
+				sanitized[key] = []string{redacted}
+				break
+			}
+		}
+	}
+	return sanitized.Encode()
+}
+
+// sanitizeBody returns the response body for logging, with the access token of token responses redacted.
+// Token responses that cannot be decoded are redacted entirely.
+func sanitizeBody(endpoint string, body []byte) string {
+	if endpoint != tokenEndpoint {
+		return string(body)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return redacted
+	}
+	if _, ok := fields["accessToken"]; ok {
+		fields["accessToken"], _ = json.Marshal(redacted)
+	}
+	sanitized, err := json.Marshal(fields)
+	if err != nil {
+		return redacted
+	}
+	return string(sanitized)
+}
+
+// sanitizeHeader returns a copy of the given request headers, with the Authorization header redacted.
+func sanitizeHeader(header http.Header) http.Header {
+	sanitized := header.Clone()
+	if sanitized.Get("Authorization") != "" {
+		sanitized.Set("Authorization", "Bearer "+redacted)
+	}
+	return sanitized
+}
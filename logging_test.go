@@ -0,0 +1,178 @@
+package applemaps
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type logRecord struct {
+	level string
+	msg   string
+	args  map[string]any
+}
+
+type recordingLogger struct {
+	mu      sync.Mutex
+	records []logRecord
+}
+
+func (l *recordingLogger) log(level, msg string, args []any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	record := logRecord{level: level, msg: msg, args: map[string]any{}}
+	for i := 0; i+1 < len(args); i += 2 {
+		record.args[fmt.Sprint(args[i])] = args[i+1]
+	}
+	l.records = append(l.records, record)
+}
+
+func (l *recordingLogger) Debug(msg string, args ...any) { l.log("debug", msg, args) }
+func (l *recordingLogger) Info(msg string, args ...any)  { l.log("info", msg, args) }
+func (l *recordingLogger) Warn(msg string, args ...any)  { l.log("warn", msg, args) }
+func (l *recordingLogger) Error(msg string, args ...any) { l.log("error", msg, args) }
+
+func TestWithLogger(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/geocode", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(geocode_SuccessResponse))
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	logger := &recordingLogger{}
+	mapsClient := NewAppleMaps(testServer.Client(), "secret-jwt", WithCustomURL(testServer.URL), WithLogger(logger), WithVerboseLogging())
+	_, err := mapsClient.Geocode(context.Background(), "test query")
+	assert.NoError(t, err)
+
+	var msgs []string
+	for _, record := range logger.records {
+		msgs = append(msgs, record.msg)
+		args := fmt.Sprint(record.args)
+		assert.NotContains(t, args, "secret-jwt")
+		assert.NotContains(t, args, "thisis.thejwt.token")
+	}
+	assert.Equal(t, []string{
+		"apple maps request",
+		"apple maps response body",
+		"apple maps access token renewed",
+		"apple maps request",
+		"apple maps response body",
+	}, msgs)
+
+	assert.JSONEq(t, `{"accessToken":"[REDACTED]","expiresInSeconds":1800}`, logger.records[1].args["body"].(string))
+
+	geocode := logger.records[3]
+	assert.Equal(t, "debug", geocode.level)
+	assert.Equal(t, geocodeEndpoint, geocode.args["endpoint"])
+	assert.Equal(t, "q=test+query", geocode.args["query"])
+	assert.Equal(t, http.StatusOK, geocode.args["status"])
+	assert.Equal(t, len(geocode_SuccessResponse), geocode.args["bytes"])
+	assert.Contains(t, fmt.Sprint(geocode.args["header"]), "Bearer [REDACTED]")
+	assert.Equal(t, geocode_SuccessResponse, logger.records[4].args["body"])
+}
+
+func TestWithLogger_Retry(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/geocode", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	logger := &recordingLogger{}
+	policy := testRetryPolicy()
+	policy.MaxAttempts = 2
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL), WithLogger(logger), WithRetryPolicy(policy))
+	_, err := mapsClient.Geocode(context.Background(), "test query")
+	assert.Error(t, err)
+
+	var levels []string
+	for _, record := range logger.records {
+		levels = append(levels, record.level+" "+record.msg)
+	}
+	assert.Equal(t, []string{
+		"debug apple maps request",
+		"info apple maps access token renewed",
+		"warn apple maps request failed",
+		"info retrying apple maps request",
+		"warn apple maps request failed",
+	}, levels)
+	assert.Equal(t, 2, logger.records[4].args["attempt"])
+}
+
+// blockingLogger blocks when logging the renewal of the access token, until it is released.
+type blockingLogger struct {
+	recordingLogger
+	entered, release chan struct{}
+}
+
+func (l *blockingLogger) Info(msg string, args ...any) {
+	if msg == "apple maps access token renewed" {
+		close(l.entered)
+		<-l.release
+	}
+	l.recordingLogger.Info(msg, args...)
+}
+
+func TestWithLogger_Blocking(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/geocode", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(geocode_SuccessResponse))
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	logger := &blockingLogger{entered: make(chan struct{}), release: make(chan struct{})}
+	defer close(logger.release)
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL), WithLogger(logger))
+	go mapsClient.Geocode(context.Background(), "test query")
+	<-logger.entered
+
+	// the renewed token is used by other requests while the renewal is still being logged
+	done := make(chan error, 1)
+	go func() {
+		_, err := mapsClient.Geocode(context.Background(), "test query")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Error("request blocked by the logger")
+	}
+}
+
+func TestSanitizeValues(t *testing.T) {
+	values := map[string][]string{"q": {"test"}, "accessToken": {"abc"}, "apiKey": {"def"}}
+	sanitized := sanitizeValues(values)
+	assert.Equal(t, "accessToken=%5BREDACTED%5D&apiKey=%5BREDACTED%5D&q=test", sanitized)
+	assert.False(t, strings.Contains(sanitized, "abc"))
+}
+
+func TestSanitizeBody(t *testing.T) {
+	assert.Equal(t, geocode_SuccessResponse, sanitizeBody(geocodeEndpoint, []byte(geocode_SuccessResponse)))
+	assert.Equal(t, redacted, sanitizeBody(tokenEndpoint, []byte("thisis.thejwt.token")))
+	assert.JSONEq(t, `{"error":{"message":"Not Authorized"}}`, sanitizeBody(tokenEndpoint, []byte(`{"error":{"message":"Not Authorized"}}`)))
+}
@@ -0,0 +1,42 @@
+package applemaps
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCache_Eviction(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(2)
+	assert.NoError(t, cache.Set(ctx, "a", []byte("1"), time.Minute))
+	assert.NoError(t, cache.Set(ctx, "b", []byte("2"), time.Minute))
+
+	// a is used more recently than b now, so b gets evicted
+	_, ok, _ := cache.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.NoError(t, cache.Set(ctx, "c", []byte("3"), time.Minute))
+
+	_, ok, _ = cache.Get(ctx, "b")
+	assert.False(t, ok)
+	value, ok, err := cache.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+	assert.Equal(t, 2, cache.Len())
+}
+
+func TestLRUCache_Expiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	cache := NewLRUCache(2)
+	cache.now = func() time.Time { return now }
+	assert.NoError(t, cache.Set(ctx, "a", []byte("1"), time.Minute))
+
+	now = now.Add(time.Minute)
+	_, ok, _ := cache.Get(ctx, "a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, cache.Len())
+}
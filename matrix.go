@@ -0,0 +1,130 @@
+package applemaps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// MatrixOptions configures the requests made by Matrix.
+type MatrixOptions struct {
+	// Concurrency is the maximum number of concurrent Etas requests. Defaults to 4.
+	Concurrency int
+	// Symmetric assumes that travelling from A to B takes as long as travelling from B to A, which is a good
+	// approximation for walking. Only the ETAs above the diagonal are requested, and mirrored below it.
+	// It is only used if the origins and destinations are the same locations in the same order.
+	Symmetric bool
+}
+
+// MatrixResponse contains the distances and travel times between all origins and destinations.
+type MatrixResponse struct {
+	Origins      []Location
+	Destinations []Location
+	// Etas contains one row per origin, with one entry per destination.
+	// The entries that could not be requested, or that the API returned no ETA for, are nil.
+	Etas [][]*Eta
+	// Errors contains the errors of the failed requests.
+	Errors []*MatrixError
+}
+
+// MatrixError describes a failed request for the origin with index Origin
+// and the destinations with indexes Start to End-1.
+type MatrixError struct {
+	Origin int
+	Start  int
+	End    int
+	Err    error
+}
+
+func (e *MatrixError) Error() string {
+	return fmt.Sprintf("origin %d, destinations %d to %d: %s", e.Origin, e.Start, e.End-1, e.Err)
+}
+
+func (e *MatrixError) Unwrap() error {
+	return e.Err
+}
+
+// At returns the ETA from the origin with index i to the destination with index j, or nil if it is not available.
+func (m *MatrixResponse) At(i, j int) *Eta {
+	if i < 0 || i >= len(m.Etas) || j < 0 || j >= len(m.Etas[i]) {
+		return nil
+	}
+	return m.Etas[i][j]
+}
+
+// Matrix returns the distances and travel times from every origin to every destination, using Etas requests
+// of up to MaxEtaDestinations destinations each.
+// The response is always returned. If any request failed, the error joins the *MatrixError of every failed request,
+// while the ETAs of the successful requests are still part of the response.
+func Matrix(ctx context.Context, c Client, origins, destinations []Location, options MatrixOptions, opts ...RequestOption) (*MatrixResponse, error) {
+	res := &MatrixResponse{
+		Origins:      origins,
+		Destinations: destinations,
+		Etas:         make([][]*Eta, len(origins)),
+	}
+	for i := range res.Etas {
+		res.Etas[i] = make([]*Eta, len(destinations))
+	}
+	if len(origins) == 0 || len(destinations) == 0 {
+		return res, errors.New("origins and destinations cannot be empty")
+	}
+
+	symmetric := options.Symmetric && sameLocations(origins, destinations)
+	var chunks []*etaChunk
+	origin := make(map[*etaChunk]int)
+	for i := range origins {
+		first := 0
+		if symmetric {
+			// only request the destinations after the origin, the others are mirrored or on the diagonal
+			first = i + 1
+		}
+		for _, chunk := range splitEtaChunks(origins[i], destinations[first:], res.Etas[i][first:]) {
+			chunk.start += first
+			origin[chunk] = i
+			chunks = append(chunks, chunk)
+		}
+	}
+	requestEtaChunks(ctx, c, chunks, options.Concurrency, opts)
+
+	if symmetric {
+		mirrorEtas(res.Etas, destinations)
+	}
+
+	var joined []error
+	for _, chunk := range chunks {
+		if chunk.err != nil {
+			err := &MatrixError{Origin: origin[chunk], Start: chunk.start, End: chunk.start + len(chunk.destinations), Err: chunk.err}
+			res.Errors = append(res.Errors, err)
+			joined = append(joined, err)
+		}
+	}
+	return res, errors.Join(joined...)
+}
+
+// mirrorEtas fills the diagonal and the entries below it of a square matrix, using the entries above the diagonal.
+func mirrorEtas(etas [][]*Eta, locations []Location) {
+	for i := range etas {
+		etas[i][i] = &Eta{Destination: locations[i]}
+		for j := i + 1; j < len(etas); j++ {
+			if etas[i][j] == nil {
+				continue
+			}
+			mirrored := *etas[i][j]
+			mirrored.Destination = locations[i]
+			etas[j][i] = &mirrored
+		}
+	}
+}
+
+// sameLocations reports whether both slices contain the same locations in the same order.
+func sameLocations(a, b []Location) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
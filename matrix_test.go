@@ -0,0 +1,85 @@
+package applemaps
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatrix(t *testing.T) {
+	var maxConcurrent int32
+	testServer := newEtasTestServer(t, &maxConcurrent)
+	defer testServer.Close()
+
+	origins := []Location{NewLocation(0, 0), NewLocation(5, 0), NewLocation(30, 0)}
+	destinations := make([]Location, 12)
+	for i := range destinations {
+		destinations[i] = NewLocation(float64(i), 0)
+	}
+
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL))
+	res, err := Matrix(context.Background(), mapsClient, origins, destinations, MatrixOptions{Concurrency: 2})
+	assert.NoError(t, err)
+	assert.Len(t, res.Etas, 3)
+	for i, origin := range origins {
+		assert.Len(t, res.Etas[i], 12)
+		for j, destination := range destinations {
+			assert.Equal(t, int(math.Abs(destination.Latitude-origin.Latitude))*1000, res.At(i, j).DistanceMeters)
+		}
+	}
+	assert.Nil(t, res.At(3, 0))
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxConcurrent), int32(2))
+}
+
+func TestMatrix_Symmetric(t *testing.T) {
+	var maxConcurrent int32
+	testServer := newEtasTestServer(t, &maxConcurrent)
+	defer testServer.Close()
+
+	var requests int32
+	countRequests := func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			if req.Endpoint == etasEndpoint {
+				atomic.AddInt32(&requests, 1)
+			}
+			return next(ctx, req)
+		}
+	}
+
+	locations := []Location{NewLocation(0, 0), NewLocation(2, 0), NewLocation(7, 0)}
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL), WithMiddleware(countRequests))
+	res, err := Matrix(context.Background(), mapsClient, locations, locations, MatrixOptions{Symmetric: true}, WithTransportType("Walking"))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&requests))
+	for i := range locations {
+		for j := range locations {
+			assert.Equal(t, int(math.Abs(locations[i].Latitude-locations[j].Latitude))*1000, res.At(i, j).DistanceMeters)
+			assert.Equal(t, locations[j], res.At(i, j).Destination)
+		}
+	}
+}
+
+func TestMatrix_PartialFailure(t *testing.T) {
+	var maxConcurrent int32
+	testServer := newEtasTestServer(t, &maxConcurrent)
+	defer testServer.Close()
+
+	origins := []Location{NewLocation(0, 0), NewLocation(1, 0)}
+	destinations := []Location{NewLocation(2, 0), NewLocation(-89, 0)}
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL))
+	res, err := Matrix(context.Background(), mapsClient, origins, destinations, MatrixOptions{})
+	assert.ErrorIs(t, err, ErrBadRequest)
+	assert.Len(t, res.Errors, 2)
+	assert.Equal(t, 0, res.Errors[0].Origin)
+	assert.Equal(t, 1, res.Errors[1].Origin)
+	assert.Nil(t, res.At(0, 0))
+}
+
+func TestMatrix_Empty(t *testing.T) {
+	_, err := Matrix(context.Background(), NewAppleMaps(http.DefaultClient, "jwt"), nil, []Location{NewLocation(0, 0)}, MatrixOptions{})
+	assert.Error(t, err)
+}
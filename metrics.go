@@ -0,0 +1,50 @@
+package applemaps
+
+import (
+	"context"
+	"time"
+)
+
+// Metrics is the interface used by the client to report its activity to a metrics system.
+// Implementations must be safe for concurrent use. See the prometheus subpackage for an implementation
+// exposing the metrics in the Prometheus text exposition format.
+type Metrics interface {
+	// ObserveRequest is called after every request attempt sent to the API, including access token requests.
+	// The status code is zero if no response was received.
+	ObserveRequest(endpoint string, statusCode int, latency time.Duration)
+	// ObserveRetry is called whenever a request to the endpoint is retried.
+	ObserveRetry(endpoint string)
+	// ObserveTokenRenewal is called after every attempt to renew the access token.
+	ObserveTokenRenewal(success bool)
+	// ObserveCache is called on every cache hit or miss of a CachingClient created with WithCacheMetrics().
+	ObserveCache(endpoint string, hit bool)
+}
+
+// WithMetrics returns a functional ClientOption used to report requests, retries and access token renewals
+// when creating a new Apple Maps API Client using NewAppleMaps().
+func WithMetrics(metrics Metrics) ClientOption {
+	return func(c *client) {
+		c.metrics = metrics
+	}
+}
+
+// WithCacheMetrics returns a CacheOption used to report cache hits and misses
+// when creating a new CachingClient using NewCachingClient().
+// It replaces a function set using WithCacheObserver().
+func WithCacheMetrics(metrics Metrics) CacheOption {
+	return WithCacheObserver(metrics.ObserveCache)
+}
+
+// observeRequests is a Middleware that reports every request attempt to the client's Metrics.
+func (c *client) observeRequests(next Handler) Handler {
+	return func(ctx context.Context, req *Request) (*Response, error) {
+		start := time.Now()
+		res, err := next(ctx, req)
+		var statusCode int
+		if res != nil {
+			statusCode = res.StatusCode
+		}
+		c.metrics.ObserveRequest(req.Endpoint, statusCode, time.Since(start))
+		return res, err
+	}
+}
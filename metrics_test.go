@@ -0,0 +1,81 @@
+package applemaps
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingMetrics struct {
+	mu            sync.Mutex
+	requests      map[string][]int
+	retries       map[string]int
+	tokenRenewals []bool
+	cache         map[string][]bool
+}
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{requests: map[string][]int{}, retries: map[string]int{}, cache: map[string][]bool{}}
+}
+
+func (m *recordingMetrics) ObserveRequest(endpoint string, statusCode int, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[endpoint] = append(m.requests[endpoint], statusCode)
+}
+
+func (m *recordingMetrics) ObserveRetry(endpoint string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries[endpoint]++
+}
+
+func (m *recordingMetrics) ObserveTokenRenewal(success bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokenRenewals = append(m.tokenRenewals, success)
+}
+
+func (m *recordingMetrics) ObserveCache(endpoint string, hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cache[endpoint] = append(m.cache[endpoint], hit)
+}
+
+func TestWithMetrics(t *testing.T) {
+	var calls int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(search_SuccessResponse))
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	metrics := newRecordingMetrics()
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL), WithMetrics(metrics), WithRetryPolicy(testRetryPolicy()))
+	cachingClient := NewCachingClient(mapsClient, NewLRUCache(10), WithCacheMetrics(metrics))
+	for i := 0; i < 2; i++ {
+		_, err := cachingClient.Search(context.Background(), "test query")
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, map[string][]int{tokenEndpoint: {200}, searchEndpoint: {502, 200}}, metrics.requests)
+	assert.Equal(t, map[string]int{searchEndpoint: 1}, metrics.retries)
+	assert.Equal(t, []bool{true}, metrics.tokenRenewals)
+	assert.Equal(t, map[string][]bool{searchEndpoint: {false, true}}, metrics.cache)
+}
@@ -0,0 +1,81 @@
+package applemaps
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+type (
+	// Request describes a single attempt of an API request, as seen by the middleware chain.
+	// Middleware may modify the Values and Header before passing the request on.
+	Request struct {
+		// Endpoint is the name of the API endpoint, for example "geocode" or "token".
+		Endpoint string
+		// Path is the URL path of the request relative to the base URL. It equals the Endpoint,
+		// except for endpoints with path parameters such as "place", where it contains the place ID.
+		Path string
+		// Values contains the query parameters of the request.
+		Values url.Values
+		// Header contains the request headers, including the Authorization header.
+		Header http.Header
+		// Attempt is the number of the attempt, starting at 1. Access token requests always have attempt 1.
+		Attempt int
+	}
+
+	// Response describes the response to a single attempt of an API request.
+	Response struct {
+		// StatusCode is the HTTP status code of the response.
+		StatusCode int
+		// Header contains the response headers.
+		Header http.Header
+		// Body is the response body. It has already been read into memory and the underlying connection released.
+		// Middleware replacing the body must return a body that can be decoded like the one returned by the API.
+		// A nil body fails the request with ErrUnexpectedResponse.
+		Body io.Reader
+	}
+
+	// Handler performs an API request. If the API responds with an error, the handler returns the Response
+	// along with an *APIError, otherwise the error is nil. If no response was received, the Response is nil.
+	Handler func(ctx context.Context, req *Request) (*Response, error)
+
+	// Middleware wraps a Handler to observe or modify requests and responses.
+	// A middleware can short-circuit the request by returning a Response or error without calling next.
+	Middleware func(next Handler) Handler
+)
+
+// WithMiddleware returns a functional ClientOption used to add middleware to the request pipeline
+// when creating a new Apple Maps API Client using NewAppleMaps().
+// The middleware applies to all requests, including access token requests, and to every attempt of a request.
+// Middleware is called in the given order, the first middleware being the outermost.
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// handler returns the send method wrapped by the middleware chain of the client.
+// Request metrics and logging are the innermost middleware, so the requests are observed as they are sent.
+func (c *client) handler() Handler {
+	h := c.send
+	if c.metrics != nil {
+		h = c.observeRequests(h)
+	}
+	if c.logger != nil {
+		h = c.logRequests(h)
+	}
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		h = c.middleware[i](h)
+	}
+	return h
+}
+
+// cloneValues returns a deep copy of the given query parameters, or empty parameters if they are nil.
+func cloneValues(values url.Values) url.Values {
+	clone := make(url.Values, len(values))
+	for key, v := range values {
+		clone[key] = append([]string(nil), v...)
+	}
+	return clone
+}
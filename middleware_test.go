@@ -0,0 +1,107 @@
+package applemaps
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware_Observe(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/geocode", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "injected", r.Header.Get("X-Test"))
+		assert.Equal(t, "DE", r.URL.Query().Get("limitToCountries"))
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(badRequest_ErrorResponse))
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	var mu sync.Mutex
+	var seen []string
+	observe := func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			res, err := next(ctx, req)
+			mu.Lock()
+			defer mu.Unlock()
+			seen = append(seen, req.Endpoint)
+			if req.Endpoint == geocodeEndpoint {
+				assert.Equal(t, 1, req.Attempt)
+				assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+				assert.ErrorIs(t, err, ErrBadRequest)
+			}
+			return res, err
+		}
+	}
+	mutate := func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			req.Header.Set("X-Test", "injected")
+			if req.Endpoint != tokenEndpoint {
+				req.Values.Set("limitToCountries", "DE")
+			}
+			return next(ctx, req)
+		}
+	}
+
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL), WithMiddleware(observe, mutate))
+	_, err := mapsClient.Geocode(context.Background(), "test query", WithLimitToCountries("US"))
+	assert.ErrorIs(t, err, ErrBadRequest)
+	assert.Equal(t, []string{tokenEndpoint, geocodeEndpoint}, seen)
+}
+
+func TestMiddleware_ShortCircuit(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request expected")
+	}))
+	defer testServer.Close()
+
+	stub := func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			body := accessToken_SuccessResponse
+			if req.Endpoint == etasEndpoint {
+				body = etas_SuccessResponse
+			}
+			return &Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: strings.NewReader(body)}, nil
+		}
+	}
+
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL), WithMiddleware(stub))
+	res, err := mapsClient.Etas(context.Background(), NewLocation(1, 1), []Location{NewLocation(1, 1)})
+	assert.NoError(t, err)
+	assert.Len(t, res.Etas, 1)
+}
+
+func TestMiddleware_ShortCircuit_NilBody(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request expected")
+	}))
+	defer testServer.Close()
+
+	stub := func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			if req.Endpoint == tokenEndpoint {
+				return &Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: strings.NewReader(accessToken_SuccessResponse)}, nil
+			}
+			return &Response{StatusCode: http.StatusOK}, nil
+		}
+	}
+
+	for _, options := range [][]ClientOption{
+		{WithMiddleware(stub)},
+		{WithMiddleware(stub), WithLogger(&recordingLogger{}), WithVerboseLogging()},
+	} {
+		options = append(options, WithCustomURL(testServer.URL))
+		mapsClient := NewAppleMaps(testServer.Client(), "jwt", options...)
+		_, err := mapsClient.Geocode(context.Background(), "test query")
+		assert.ErrorIs(t, err, ErrUnexpectedResponse)
+	}
+}
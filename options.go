@@ -132,6 +132,17 @@ func WithUserLocation(location Location) RequestOption {
 	}
 }
 
+// WithPageToken provides an option to request a specific page of search results, using the NextPageToken or PrevPageToken
+// from the PaginationInfo of a previous SearchResponse.
+func WithPageToken(token string) RequestOption {
+	return func(v url.Values) {
+		if token == "" {
+			return
+		}
+		v.Set("pageToken", token)
+	}
+}
+
 // queryParameterString formats slices of different data types to a string representation that can be used for query parameters.
 func queryParameterString[P []Category | []Location | []Avoid | []string](params P) string {
 	var str = make([]string, len(params))
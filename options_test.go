@@ -218,3 +218,16 @@ func TestWithUserLocation(t *testing.T) {
 
 	assert.Equal(t, expected, vals)
 }
+
+func TestWithPageToken(t *testing.T) {
+	vals := url.Values{}
+	vals.Add("q", "testing")
+	WithPageToken("")(vals)
+	WithPageToken("next")(vals)
+
+	expected := url.Values{}
+	expected.Add("q", "testing")
+	expected.Add("pageToken", "next")
+
+	assert.Equal(t, expected, vals)
+}
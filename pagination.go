@@ -0,0 +1,92 @@
+package applemaps
+
+import "context"
+
+// SearchIterator iterates over the places of all pages of a search, requesting each page only when it is needed.
+// Use Next to advance to the next place, Place to retrieve it and Err to check for errors once Next returns false.
+type SearchIterator struct {
+	ctx        context.Context
+	client     Client
+	query      string
+	opts       []RequestOption
+	maxResults int
+
+	page      []Place
+	index     int
+	returned  int
+	nextToken string
+	seen      map[string]bool
+	done      bool
+	current   Place
+	err       error
+}
+
+// SearchAll returns a SearchIterator over the places matching the query, following the pagination of the search results.
+// At most maxResults places are returned, or all of them if maxResults is zero or less.
+// The iteration stops with the context error once ctx is done.
+func SearchAll(ctx context.Context, c Client, query string, maxResults int, opts ...RequestOption) *SearchIterator {
+	return &SearchIterator{
+		ctx:        ctx,
+		client:     c,
+		query:      query,
+		opts:       opts,
+		maxResults: maxResults,
+		seen:       make(map[string]bool),
+	}
+}
+
+// Next advances the iterator to the next place, requesting the next page of results if needed.
+// It returns false when all places have been returned, the maximum number of results is reached, or an error occurred.
+func (it *SearchIterator) Next() bool {
+	if it.err != nil || (it.maxResults > 0 && it.returned >= it.maxResults) {
+		return false
+	}
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+	for it.index >= len(it.page) {
+		if it.done {
+			return false
+		}
+		if !it.fetch() {
+			return false
+		}
+	}
+	it.current = it.page[it.index]
+	it.index++
+	it.returned++
+	return true
+}
+
+// Place returns the current place. It is only valid after a call to Next returned true.
+func (it *SearchIterator) Place() Place {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *SearchIterator) Err() error {
+	return it.err
+}
+
+// fetch requests the next page of results. It returns false if the request failed.
+func (it *SearchIterator) fetch() bool {
+	opts := it.opts
+	if it.nextToken != "" {
+		opts = append(append([]RequestOption(nil), it.opts...), WithPageToken(it.nextToken))
+	}
+	res, err := it.client.Search(it.ctx, it.query, opts...)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.page, it.index = res.Results, 0
+	it.nextToken = res.PaginationInfo.NextPageToken
+	// stop on the last page, and guard against a server returning the same page token twice
+	if it.nextToken == "" || it.seen[it.nextToken] {
+		it.done = true
+	}
+	it.seen[it.nextToken] = true
+	return true
+}
@@ -0,0 +1,89 @@
+package applemaps
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PaginationTestSuite struct {
+	suite.Suite
+	testServer  *httptest.Server
+	mapsClient  Client
+	searchCalls int32
+}
+
+func (s *PaginationTestSuite) SetupSuite() {
+	pages := map[string]string{
+		"":      `{"results":[{"name":"Place 1"},{"name":"Place 2"}],"paginationInfo":{"nextPageToken":"page2","totalPageCount":3,"totalResults":5}}`,
+		"page2": `{"results":[{"name":"Place 3"},{"name":"Place 4"}],"paginationInfo":{"nextPageToken":"page3","prevPageToken":"page1","totalPageCount":3,"totalResults":5}}`,
+		"page3": `{"results":[{"name":"Place 5"}],"paginationInfo":{"prevPageToken":"page2","totalPageCount":3,"totalResults":5}}`,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&s.searchCalls, 1)
+		s.Equal("Cafe", r.URL.Query().Get("includePoiCategories"))
+		page, ok := pages[r.URL.Query().Get("pageToken")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(page))
+	})
+	s.testServer = httptest.NewServer(mux)
+	s.mapsClient = NewAppleMaps(s.testServer.Client(), "jwt", WithCustomURL(s.testServer.URL))
+}
+
+func (s *PaginationTestSuite) SetupTest() {
+	atomic.StoreInt32(&s.searchCalls, 0)
+}
+
+func (s *PaginationTestSuite) TearDownSuite() {
+	s.testServer.Close()
+}
+
+func (s *PaginationTestSuite) TestSearchAll() {
+	it := SearchAll(context.Background(), s.mapsClient, "coffee", 0, WithIncludePoiCategories(Cafe))
+	var names []string
+	for it.Next() {
+		names = append(names, it.Place().Name)
+	}
+	s.NoError(it.Err())
+	s.Equal([]string{"Place 1", "Place 2", "Place 3", "Place 4", "Place 5"}, names)
+	s.EqualValues(3, s.searchCalls)
+}
+
+func (s *PaginationTestSuite) TestSearchAll_MaxResults() {
+	it := SearchAll(context.Background(), s.mapsClient, "coffee", 3, WithIncludePoiCategories(Cafe))
+	var count int
+	for it.Next() {
+		count++
+		s.Equal(fmt.Sprintf("Place %d", count), it.Place().Name)
+	}
+	s.NoError(it.Err())
+	s.Equal(3, count)
+	s.EqualValues(2, s.searchCalls)
+}
+
+func (s *PaginationTestSuite) TestSearchAll_ContextCanceled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	it := SearchAll(ctx, s.mapsClient, "coffee", 0, WithIncludePoiCategories(Cafe))
+	s.True(it.Next())
+	cancel()
+	s.False(it.Next())
+	s.ErrorIs(it.Err(), context.Canceled)
+}
+
+func TestPaginationTestSuite(t *testing.T) {
+	suite.Run(t, new(PaginationTestSuite))
+}
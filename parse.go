@@ -0,0 +1,224 @@
+package applemaps
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// coordinate units of the numbers of a textual location.
+const (
+	unitNone = iota
+	unitDegrees
+	unitMinutes
+	unitSeconds
+)
+
+// locationToken is a number or hemisphere letter of a textual location.
+type locationToken struct {
+	hemisphere rune
+	number     string
+	unit       int
+}
+
+// coordinate is a latitude or longitude parsed from text, with its hemisphere if one was given.
+type coordinate struct {
+	value      float64
+	hemisphere rune
+}
+
+// ParseLocation parses a location from text in one of the following formats:
+//
+//   - decimal degrees separated by a comma, a pipe or spaces: "48.8582,2.2945", "48.8582|2.2945"
+//   - degrees, minutes and seconds: 48°51'29.6"N 2°17'40.2"E
+//   - degrees and decimal minutes: 48°51.493'N 2°17.67'E
+//   - geo URIs as defined by RFC 5870: "geo:48.8582,2.2945;u=35"
+//
+// The latitude comes first, unless the hemisphere letters (N, S, E, W) say otherwise. Hemisphere letters can be placed
+// before or after a coordinate, and coordinates in the southern and western hemispheres can also be given as negative numbers.
+// The text returned by Location.String() is parsed into the same location.
+// Errors wrap ErrInvalidCoordinates, including those for locations out of range.
+func ParseLocation(s string) (Location, error) {
+	var l Location
+	var err error
+	if trimmed := strings.TrimSpace(s); len(trimmed) >= 4 && strings.EqualFold(trimmed[:4], "geo:") {
+		l, err = parseGeoURI(trimmed[4:])
+	} else {
+		l, err = parseCoordinatePair(s)
+	}
+	if err != nil {
+		return Location{}, fmt.Errorf("parse location %q: %w", s, err)
+	}
+	if err := l.Validate(); err != nil {
+		return Location{}, fmt.Errorf("parse location %q: %w", s, err)
+	}
+	return l, nil
+}
+
+// parseGeoURI parses the part of a geo URI after the scheme, which contains the coordinates, an optional altitude
+// and optional parameters. The only supported coordinate reference system is WGS 84.
+func parseGeoURI(s string) (Location, error) {
+	parts := strings.Split(s, ";")
+	for _, param := range parts[1:] {
+		name, value, _ := strings.Cut(param, "=")
+		if strings.EqualFold(name, "crs") && !strings.EqualFold(value, "wgs84") {
+			return Location{}, fmt.Errorf("%w: unsupported coordinate reference system %q", ErrInvalidCoordinates, value)
+		}
+	}
+	coordinates := strings.Split(parts[0], ",")
+	if len(coordinates) != 2 && len(coordinates) != 3 {
+		return Location{}, fmt.Errorf("%w: geo URI must contain two or three coordinates", ErrInvalidCoordinates)
+	}
+	values := make([]float64, len(coordinates))
+	for i, c := range coordinates {
+		var err error
+		if values[i], err = strconv.ParseFloat(c, 64); err != nil {
+			return Location{}, fmt.Errorf("%w: invalid number %q", ErrInvalidCoordinates, c)
+		}
+	}
+	return NewLocation(values[0], values[1]), nil
+}
+
+// parseCoordinatePair parses two coordinates in decimal degrees, degrees and decimal minutes,
+// or degrees, minutes and seconds.
+func parseCoordinatePair(s string) (Location, error) {
+	tokens, err := tokenizeLocation(s)
+	if err != nil {
+		return Location{}, err
+	}
+	first, tokens, err := parseCoordinate(tokens)
+	if err != nil {
+		return Location{}, err
+	}
+	second, tokens, err := parseCoordinate(tokens)
+	if err != nil {
+		return Location{}, err
+	}
+	if len(tokens) > 0 {
+		return Location{}, fmt.Errorf("%w: unexpected text after longitude", ErrInvalidCoordinates)
+	}
+
+	firstLat := first.hemisphere == 0 || first.hemisphere == 'N' || first.hemisphere == 'S'
+	secondLat := second.hemisphere == 'N' || second.hemisphere == 'S'
+	switch {
+	case firstLat && !secondLat:
+		return NewLocation(first.value, second.value), nil
+	case !firstLat && (secondLat || second.hemisphere == 0):
+		return NewLocation(second.value, first.value), nil
+	case first.hemisphere == 0 && secondLat:
+		// only the second coordinate is marked as latitude, so the first one is the longitude
+		return NewLocation(second.value, first.value), nil
+	default:
+		return Location{}, fmt.Errorf("%w: both coordinates are in the same direction", ErrInvalidCoordinates)
+	}
+}
+
+// parseCoordinate parses a single coordinate from the beginning of tokens, and returns the remaining tokens.
+// A hemisphere letter following the numbers belongs to the coordinate, unless it already has a leading one.
+func parseCoordinate(tokens []locationToken) (coordinate, []locationToken, error) {
+	var c coordinate
+	if len(tokens) > 0 && tokens[0].hemisphere != 0 {
+		c.hemisphere, tokens = tokens[0].hemisphere, tokens[1:]
+	}
+	if len(tokens) == 0 || tokens[0].hemisphere != 0 || (tokens[0].unit != unitNone && tokens[0].unit != unitDegrees) {
+		return c, nil, fmt.Errorf("%w: missing degrees", ErrInvalidCoordinates)
+	}
+
+	degrees, _ := strconv.ParseFloat(tokens[0].number, 64)
+	negative := strings.HasPrefix(tokens[0].number, "-")
+	isInteger := !strings.Contains(tokens[0].number, ".")
+	tokens = tokens[1:]
+	value := degrees
+	var fraction float64
+	for _, u := range []struct {
+		unit    int
+		divisor float64
+	}{{unitMinutes, 60}, {unitSeconds, 3600}} {
+		if len(tokens) == 0 || tokens[0].unit != u.unit {
+			break
+		}
+		if !isInteger || negative && tokens[0].number[0] == '-' {
+			return c, nil, fmt.Errorf("%w: invalid minutes or seconds %q", ErrInvalidCoordinates, tokens[0].number)
+		}
+		v, _ := strconv.ParseFloat(tokens[0].number, 64)
+		if v < 0 || v >= 60 {
+			return c, nil, fmt.Errorf("%w: minutes or seconds %v out of range [0, 60)", ErrInvalidCoordinates, v)
+		}
+		fraction += v / u.divisor
+		isInteger = !strings.Contains(tokens[0].number, ".")
+		tokens = tokens[1:]
+	}
+	if negative {
+		value -= fraction
+	} else {
+		value += fraction
+	}
+	if len(tokens) > 0 && tokens[0].unit == unitSeconds {
+		return c, nil, fmt.Errorf("%w: seconds without minutes", ErrInvalidCoordinates)
+	}
+
+	if c.hemisphere == 0 && len(tokens) > 0 && tokens[0].hemisphere != 0 {
+		c.hemisphere, tokens = tokens[0].hemisphere, tokens[1:]
+	}
+	if c.hemisphere != 0 && negative {
+		return c, nil, fmt.Errorf("%w: negative coordinate with hemisphere %c", ErrInvalidCoordinates, c.hemisphere)
+	}
+	if c.hemisphere == 'S' || c.hemisphere == 'W' {
+		value = -value
+	}
+	c.value = value
+	return c, tokens, nil
+}
+
+// tokenizeLocation splits the text into numbers with their units and hemisphere letters,
+// skipping spaces and the separators ',', ';' and '|'.
+func tokenizeLocation(s string) ([]locationToken, error) {
+	var tokens []locationToken
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r) || r == ',' || r == ';' || r == '|':
+			i++
+		case strings.ContainsRune("NSEWnsew", r):
+			tokens = append(tokens, locationToken{hemisphere: unicode.ToUpper(r)})
+			i++
+		case r == '-' || r == '+' || r == '.' || unicode.IsDigit(r):
+			start := i
+			i++
+			for i < len(runes) && (runes[i] == '.' || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			number := string(runes[start:i])
+			if _, err := strconv.ParseFloat(number, 64); err != nil {
+				return nil, fmt.Errorf("%w: invalid number %q", ErrInvalidCoordinates, number)
+			}
+			for i < len(runes) && unicode.IsSpace(runes[i]) {
+				i++
+			}
+			unit := unitNone
+			if i < len(runes) {
+				switch runes[i] {
+				case '°', 'º':
+					unit = unitDegrees
+				case '\'', '′', '’':
+					unit = unitMinutes
+					if i+1 < len(runes) && runes[i] == '\'' && runes[i+1] == '\'' {
+						unit = unitSeconds
+						i++
+					}
+				case '"', '″', '”':
+					unit = unitSeconds
+				}
+			}
+			if unit != unitNone {
+				i++
+			}
+			tokens = append(tokens, locationToken{number: strings.TrimPrefix(number, "+"), unit: unit})
+		default:
+			return nil, fmt.Errorf("%w: unexpected character %q", ErrInvalidCoordinates, r)
+		}
+	}
+	return tokens, nil
+}
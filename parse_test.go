@@ -0,0 +1,72 @@
+package applemaps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLocation(t *testing.T) {
+	tests := map[string]Location{
+		"48.8582,2.2945":                        NewLocation(48.8582, 2.2945),
+		" 48.8582, 2.2945 ":                     NewLocation(48.8582, 2.2945),
+		"48.8582|2.2945":                        NewLocation(48.8582, 2.2945),
+		"-33.856784 151.215297":                 NewLocation(-33.856784, 151.215297),
+		"+37.7857,-122.4011":                    NewLocation(37.7857, -122.4011),
+		`48°51'29.6"N 2°17'40.2"E`:              NewLocation(48+51.0/60+29.6/3600, 2+17.0/60+40.2/3600),
+		`48° 51′ 29.6″ N, 2° 17′ 40.2″ E`:       NewLocation(48+51.0/60+29.6/3600, 2+17.0/60+40.2/3600),
+		`33°51'24.4''S 151°12'55.1''E`:          NewLocation(-(33 + 51.0/60 + 24.4/3600), 151+12.0/60+55.1/3600),
+		`N 48°51'29.6" W 2°17'40.2"`:            NewLocation(48+51.0/60+29.6/3600, -(2 + 17.0/60 + 40.2/3600)),
+		`2°17'40.2"E 48°51'29.6"N`:              NewLocation(48+51.0/60+29.6/3600, 2+17.0/60+40.2/3600),
+		`-33°51'24.4" 151°12'55.1"`:             NewLocation(-(33 + 51.0/60 + 24.4/3600), 151+12.0/60+55.1/3600),
+		"48°51.493'N 2°17.67'E":                 NewLocation(48+51.493/60, 2+17.67/60),
+		"48.8582n 2.2945w":                      NewLocation(48.8582, -2.2945),
+		"2.2945, 48.8582N":                      NewLocation(48.8582, 2.2945),
+		"-2.2945 33.856784S":                    NewLocation(-33.856784, -2.2945),
+		"geo:48.8582,2.2945":                    NewLocation(48.8582, 2.2945),
+		"GEO:-33.856784,151.215297,42":          NewLocation(-33.856784, 151.215297),
+		"geo:48.8582,2.2945;crs=WGS84;u=35":     NewLocation(48.8582, 2.2945),
+		"geo:48.8582,2.2945;u=35;foo=bar":       NewLocation(48.8582, 2.2945),
+		NewLocation(1e-7, -179.999999).String(): NewLocation(1e-7, -179.999999),
+	}
+	for input, expected := range tests {
+		l, err := ParseLocation(input)
+		assert.NoError(t, err, input)
+		assert.InDelta(t, expected.Latitude, l.Latitude, 1e-12, input)
+		assert.InDelta(t, expected.Longitude, l.Longitude, 1e-12, input)
+	}
+}
+
+func TestParseLocation_RoundTrip(t *testing.T) {
+	for _, l := range []Location{NewLocation(51.0453064, 13.7359337), NewLocation(-90, 180), NewLocation(0.1, -0.2), NewLocation(37.78571234567891, -122.40111234567891)} {
+		parsed, err := ParseLocation(l.String())
+		assert.NoError(t, err)
+		assert.Equal(t, l, parsed)
+	}
+}
+
+func TestParseLocation_Invalid(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"48.8582",
+		"48.8582,2.2945,3",
+		"91,0",
+		"0,181",
+		"abc",
+		"48.8.5,2",
+		`48°61'N 2°E`,
+		`48°51'60"N 2°E`,
+		`48.5°30'N 2°E`,
+		`-48°N 2°E`,
+		`48°N 2°N`,
+		`48°E 2°W`,
+		`48°51"N 2°E`,
+		"geo:48.8582",
+		"geo:48.8582,abc",
+		"geo:48.8582,2.2945;crs=utm",
+		"geo:91,0",
+	} {
+		_, err := ParseLocation(input)
+		assert.ErrorIs(t, err, ErrInvalidCoordinates, input)
+	}
+}
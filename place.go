@@ -0,0 +1,47 @@
+package applemaps
+
+import (
+	"context"
+	"errors"
+	"net/url"
+)
+
+// Place returns the place with the given ID, as returned in the results of Search or Geocode.
+func (c *client) Place(ctx context.Context, id string, opts ...RequestOption) (*Place, error) {
+	if id == "" {
+		return nil, errors.New("id cannot be empty")
+	}
+	values := url.Values{}
+	for _, opt := range opts {
+		opt(values)
+	}
+	return execPath[Place](ctx, c, placeEndpoint, placeEndpoint+"/"+url.PathEscape(id), values)
+}
+
+// Places returns the places with the given IDs.
+// IDs that could not be looked up are reported in the Errors of the response instead of failing the request.
+func (c *client) Places(ctx context.Context, ids []string, opts ...RequestOption) (*PlacesResponse, error) {
+	if len(ids) == 0 {
+		return nil, errors.New("ids cannot be empty")
+	}
+	values := url.Values{}
+	values.Add("ids", queryParameterString(ids))
+	for _, opt := range opts {
+		opt(values)
+	}
+	return exec[PlacesResponse](ctx, c, placeEndpoint, values)
+}
+
+// PlaceAlternateIDs returns all alternate IDs of the places with the given IDs.
+// A place can have several IDs, for example after places were merged. Use the IDs to match stored places with new results.
+func (c *client) PlaceAlternateIDs(ctx context.Context, ids []string, opts ...RequestOption) (*AlternateIDsResponse, error) {
+	if len(ids) == 0 {
+		return nil, errors.New("ids cannot be empty")
+	}
+	values := url.Values{}
+	values.Add("ids", queryParameterString(ids))
+	for _, opt := range opts {
+		opt(values)
+	}
+	return exec[AlternateIDsResponse](ctx, c, alternateIDsEndpoint, values)
+}
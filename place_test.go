@@ -0,0 +1,93 @@
+package applemaps
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	place_SuccessResponse        string = `{"id":"I7C250D2CDCB364A","alternateIds":["I1E0F3D2B3AC1A9C5"],"coordinate":{"latitude":51.0658585,"longitude":13.7466163},"displayMapRegion":{"southLatitude":51.0613669235794,"westLongitude":13.739468964416949,"northLatitude":51.070350076420596,"eastLongitude":13.75376363558305},"name":"Königsbrücker Straße 15","formattedAddressLines":["Königsbrücker Straße 15","01099 Dresden","Germany"],"structuredAddress":{"administrativeArea":"Saxony","locality":"Dresden","postCode":"01099","thoroughfare":"Königsbrücker Straße","subThoroughfare":"15","fullThoroughfare":"Königsbrücker Straße 15"},"country":"Germany","countryCode":"DE"}`
+	places_SuccessResponse       string = `{"results":[{"id":"I7C250D2CDCB364A","coordinate":{"latitude":51.0658585,"longitude":13.7466163},"name":"Königsbrücker Straße 15","country":"Germany","countryCode":"DE"}],"errors":[{"errorCode":"FAILED_NOT_FOUND","id":"IUNKNOWN"}]}`
+	alternateIDs_SuccessResponse string = `{"results":[{"id":"I7C250D2CDCB364A","alternateIds":["I1E0F3D2B3AC1A9C5"]}],"errors":[]}`
+)
+
+type PlaceTestSuite struct {
+	suite.Suite
+	testServer *httptest.Server
+	mapsClient Client
+}
+
+func (s *PlaceTestSuite) SetupSuite() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/place/I7C250D2CDCB364A", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(place_SuccessResponse))
+	})
+	mux.HandleFunc("/place", func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("I7C250D2CDCB364A,IUNKNOWN", r.URL.Query().Get("ids"))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(places_SuccessResponse))
+	})
+	mux.HandleFunc("/place/alternateIds", func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("I7C250D2CDCB364A", r.URL.Query().Get("ids"))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(alternateIDs_SuccessResponse))
+	})
+	s.testServer = httptest.NewServer(mux)
+	s.mapsClient = NewAppleMaps(s.testServer.Client(), "jwt", WithCustomURL(s.testServer.URL))
+}
+
+func (s *PlaceTestSuite) TearDownSuite() {
+	s.testServer.Close()
+}
+
+func (s *PlaceTestSuite) TestPlace_Success() {
+	var expected = &Place{}
+	json.Unmarshal([]byte(place_SuccessResponse), expected)
+	res, err := s.mapsClient.Place(context.Background(), "I7C250D2CDCB364A")
+	s.NoError(err)
+	s.Equal(expected, res)
+	s.Equal("I7C250D2CDCB364A", res.ID)
+	s.Equal([]string{"I1E0F3D2B3AC1A9C5"}, res.AlternateIDs)
+}
+
+func (s *PlaceTestSuite) TestPlace_NotFound() {
+	_, err := s.mapsClient.Place(context.Background(), "IUNKNOWN")
+	s.ErrorIs(err, ErrNotFound)
+
+	_, err = s.mapsClient.Place(context.Background(), "")
+	s.Error(err)
+}
+
+func (s *PlaceTestSuite) TestPlaces_Success() {
+	var expected = &PlacesResponse{}
+	json.Unmarshal([]byte(places_SuccessResponse), expected)
+	res, err := s.mapsClient.Places(context.Background(), []string{"I7C250D2CDCB364A", "IUNKNOWN"})
+	s.NoError(err)
+	s.Equal(expected, res)
+	s.Equal([]PlaceLookupError{{ErrorCode: "FAILED_NOT_FOUND", ID: "IUNKNOWN"}}, res.Errors)
+
+	_, err = s.mapsClient.Places(context.Background(), nil)
+	s.Error(err)
+}
+
+func (s *PlaceTestSuite) TestPlaceAlternateIDs_Success() {
+	var expected = &AlternateIDsResponse{}
+	json.Unmarshal([]byte(alternateIDs_SuccessResponse), expected)
+	res, err := s.mapsClient.PlaceAlternateIDs(context.Background(), []string{"I7C250D2CDCB364A"})
+	s.NoError(err)
+	s.Equal(expected, res)
+}
+
+func TestPlaceTestSuite(t *testing.T) {
+	suite.Run(t, new(PlaceTestSuite))
+}
@@ -0,0 +1,108 @@
+package applemaps
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Precisions of encoded polylines, the number of decimal places the coordinates are rounded to.
+const (
+	// PolylinePrecision5 is the precision used by the Google Maps APIs.
+	PolylinePrecision5 = 5
+	// PolylinePrecision6 is the precision used by OSRM and Valhalla, among others.
+	PolylinePrecision6 = 6
+)
+
+// EncodePolyline encodes the path using the Google encoded polyline algorithm,
+// rounding the coordinates to the given number of decimal places.
+func EncodePolyline(path []Location, precision int) string {
+	factor := math.Pow10(precision)
+	var b strings.Builder
+	var prevLat, prevLng int64
+	for _, l := range path {
+		lat := int64(math.Round(l.Latitude * factor))
+		lng := int64(math.Round(l.Longitude * factor))
+		encodePolylineValue(&b, lat-prevLat)
+		encodePolylineValue(&b, lng-prevLng)
+		prevLat, prevLng = lat, lng
+	}
+	return b.String()
+}
+
+// DecodePolyline decodes a path encoded using the Google encoded polyline algorithm with the given precision.
+func DecodePolyline(encoded string, precision int) ([]Location, error) {
+	factor := math.Pow10(precision)
+	var path []Location
+	var lat, lng int64
+	for i := 0; i < len(encoded); {
+		dLat, n, err := decodePolylineValue(encoded[i:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid polyline at offset %d: %w", i, err)
+		}
+		i += n
+		if i == len(encoded) {
+			return nil, fmt.Errorf("invalid polyline at offset %d: missing longitude", i)
+		}
+		dLng, n, err := decodePolylineValue(encoded[i:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid polyline at offset %d: %w", i, err)
+		}
+		i += n
+		lat, lng = lat+dLat, lng+dLng
+		path = append(path, NewLocation(float64(lat)/factor, float64(lng)/factor))
+	}
+	return path, nil
+}
+
+// EncodedRoutePaths returns the full path of every route, as returned by RoutePath, encoded as polyline
+// with the given precision. It returns an *IndexError if any index of the response is out of range.
+func (r *DirectionsResponse) EncodedRoutePaths(precision int) ([]string, error) {
+	encoded := make([]string, len(r.Routes))
+	for i := range r.Routes {
+		path, err := r.RoutePath(i)
+		if err != nil {
+			return nil, err
+		}
+		encoded[i] = EncodePolyline(path, precision)
+	}
+	return encoded, nil
+}
+
+// encodePolylineValue writes a single signed value to b, in chunks of five bits.
+func encodePolylineValue(b *strings.Builder, value int64) {
+	v := value << 1
+	if value < 0 {
+		v = ^v
+	}
+	for v >= 0x20 {
+		b.WriteByte(byte(0x20|(v&0x1f)) + 63)
+		v >>= 5
+	}
+	b.WriteByte(byte(v) + 63)
+}
+
+// decodePolylineValue reads a single signed value from the beginning of s, and returns it with the number of bytes read.
+func decodePolylineValue(s string) (int64, int, error) {
+	var result int64
+	var shift uint
+	for i := 0; i < len(s); i++ {
+		c := int64(s[i]) - 63
+		if c < 0 || c > 0x3f {
+			return 0, 0, fmt.Errorf("invalid character %q", s[i])
+		}
+		if shift > 60 {
+			return 0, 0, errors.New("value too large")
+		}
+		result |= (c & 0x1f) << shift
+		shift += 5
+		if c < 0x20 {
+			if result&1 != 0 {
+				return ^(result >> 1), i + 1, nil
+			}
+			return result >> 1, i + 1, nil
+		}
+	}
+	return 0, 0, errors.New("unexpected end of polyline")
+}
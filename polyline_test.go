@@ -0,0 +1,62 @@
+package applemaps
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodePolyline(t *testing.T) {
+	path := []Location{NewLocation(38.5, -120.2), NewLocation(40.7, -120.95), NewLocation(43.252, -126.453)}
+	assert.Equal(t, "_p~iF~ps|U_ulLnnqC_mqNvxq`@", EncodePolyline(path, PolylinePrecision5))
+	assert.Equal(t, "", EncodePolyline(nil, PolylinePrecision5))
+}
+
+func TestDecodePolyline(t *testing.T) {
+	path, err := DecodePolyline("_p~iF~ps|U_ulLnnqC_mqNvxq`@", PolylinePrecision5)
+	assert.NoError(t, err)
+	assert.Equal(t, []Location{NewLocation(38.5, -120.2), NewLocation(40.7, -120.95), NewLocation(43.252, -126.453)}, path)
+
+	path, err = DecodePolyline("", PolylinePrecision5)
+	assert.NoError(t, err)
+	assert.Empty(t, path)
+}
+
+func TestDecodePolyline_Invalid(t *testing.T) {
+	for _, encoded := range []string{"_p~iF", "_p~iF~ps|", "_p~iF ps|U", "\x7f?"} {
+		_, err := DecodePolyline(encoded, PolylinePrecision5)
+		assert.Error(t, err, encoded)
+	}
+}
+
+func TestPolyline_RoundTrip(t *testing.T) {
+	path := []Location{NewLocation(51.042699, 13.735173), NewLocation(-33.856784, 151.215297), NewLocation(0, -0.000001), NewLocation(90, 180)}
+	for _, precision := range []int{PolylinePrecision5, PolylinePrecision6} {
+		decoded, err := DecodePolyline(EncodePolyline(path, precision), precision)
+		assert.NoError(t, err)
+		assert.Len(t, decoded, len(path))
+		for i := range path {
+			assert.InDelta(t, path[i].Latitude, decoded[i].Latitude, 0.6/math.Pow10(precision))
+			assert.InDelta(t, path[i].Longitude, decoded[i].Longitude, 0.6/math.Pow10(precision))
+		}
+	}
+}
+
+func TestDirectionsResponse_EncodedRoutePaths(t *testing.T) {
+	var res DirectionsResponse
+	assert.NoError(t, json.Unmarshal([]byte(directions_SuccessResponse), &res))
+
+	encoded, err := res.EncodedRoutePaths(PolylinePrecision6)
+	assert.NoError(t, err)
+	assert.Len(t, encoded, 1)
+	path, err := DecodePolyline(encoded[0], PolylinePrecision6)
+	assert.NoError(t, err)
+	expected, _ := res.RoutePath(0)
+	assert.Equal(t, expected, path)
+
+	res.Steps[0].StepPathIndex = 10
+	_, err = res.EncodedRoutePaths(PolylinePrecision6)
+	assert.Error(t, err)
+}
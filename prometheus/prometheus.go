@@ -0,0 +1,202 @@
+// Package prometheus implements applemaps.Metrics, exposing the collected metrics
+// in the Prometheus text exposition format without depending on a Prometheus client library.
+package prometheus
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds in seconds of the request latency histogram buckets.
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Collector collects the metrics reported by an Apple Maps client and serves them over HTTP.
+// Use it with applemaps.WithMetrics() and register it as the handler of your metrics endpoint.
+type Collector struct {
+	mu            sync.Mutex
+	namespace     string
+	buckets       []float64
+	requests      map[[2]string]uint64
+	latencies     map[string]*histogram
+	retries       map[string]uint64
+	cache         map[[2]string]uint64
+	tokenRenewals map[string]uint64
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewCollector creates a new Collector. The metric names are prefixed by the given namespace, usually "applemaps".
+// If no buckets are given, DefaultBuckets are used for the request latency histogram.
+func NewCollector(namespace string, buckets ...float64) *Collector {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &Collector{
+		namespace:     namespace,
+		buckets:       buckets,
+		requests:      make(map[[2]string]uint64),
+		latencies:     make(map[string]*histogram),
+		retries:       make(map[string]uint64),
+		cache:         make(map[[2]string]uint64),
+		tokenRenewals: make(map[string]uint64),
+	}
+}
+
+// ObserveRequest counts a request by endpoint and status class, and records its latency.
+func (c *Collector) ObserveRequest(endpoint string, statusCode int, latency time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests[[2]string{endpoint, statusClass(statusCode)}]++
+	h, ok := c.latencies[endpoint]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(c.buckets))}
+		c.latencies[endpoint] = h
+	}
+	seconds := latency.Seconds()
+	for i, bound := range c.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// ObserveRetry counts a retried request to the endpoint.
+func (c *Collector) ObserveRetry(endpoint string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.retries[endpoint]++
+}
+
+// ObserveTokenRenewal counts an access token renewal.
+func (c *Collector) ObserveTokenRenewal(success bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokenRenewals[result(success, "success", "error")]++
+}
+
+// ObserveCache counts a cache hit or miss for the endpoint.
+func (c *Collector) ObserveCache(endpoint string, hit bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache[[2]string{endpoint, result(hit, "hit", "miss")}]++
+}
+
+// ServeHTTP writes all collected metrics in the Prometheus text exposition format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = c.Write(w)
+}
+
+// Write writes all collected metrics in the Prometheus text exposition format to w.
+func (c *Collector) Write(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var b strings.Builder
+	name := c.name("requests_total")
+	writeHeader(&b, name, "counter", "Number of requests sent to the Apple Maps Server API by endpoint and status class.")
+	for _, key := range sortedPairs(c.requests) {
+		fmt.Fprintf(&b, "%s{endpoint=%q,status_class=%q} %d\n", name, key[0], key[1], c.requests[key])
+	}
+
+	name = c.name("request_duration_seconds")
+	writeHeader(&b, name, "histogram", "Latency of requests sent to the Apple Maps Server API by endpoint.")
+	for _, endpoint := range sortedKeys(c.latencies) {
+		h := c.latencies[endpoint]
+		for i, bound := range c.buckets {
+			fmt.Fprintf(&b, "%s_bucket{endpoint=%q,le=%q} %d\n", name, endpoint, formatFloat(bound), h.counts[i])
+		}
+		fmt.Fprintf(&b, "%s_bucket{endpoint=%q,le=\"+Inf\"} %d\n", name, endpoint, h.count)
+		fmt.Fprintf(&b, "%s_sum{endpoint=%q} %s\n", name, endpoint, formatFloat(h.sum))
+		fmt.Fprintf(&b, "%s_count{endpoint=%q} %d\n", name, endpoint, h.count)
+	}
+
+	name = c.name("retries_total")
+	writeHeader(&b, name, "counter", "Number of retried requests by endpoint.")
+	for _, endpoint := range sortedKeys(c.retries) {
+		fmt.Fprintf(&b, "%s{endpoint=%q} %d\n", name, endpoint, c.retries[endpoint])
+	}
+
+	name = c.name("cache_requests_total")
+	writeHeader(&b, name, "counter", "Number of cache lookups by endpoint and result.")
+	for _, key := range sortedPairs(c.cache) {
+		fmt.Fprintf(&b, "%s{endpoint=%q,result=%q} %d\n", name, key[0], key[1], c.cache[key])
+	}
+
+	name = c.name("token_renewals_total")
+	writeHeader(&b, name, "counter", "Number of access token renewals by result.")
+	for _, res := range sortedKeys(c.tokenRenewals) {
+		fmt.Fprintf(&b, "%s{result=%q} %d\n", name, res, c.tokenRenewals[res])
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (c *Collector) name(metric string) string {
+	if c.namespace == "" {
+		return metric
+	}
+	return c.namespace + "_" + metric
+}
+
+func writeHeader(b *strings.Builder, name, metricType, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+// statusClass returns the class of an HTTP status code, for example "2xx", or "error" if there was no response.
+func statusClass(statusCode int) string {
+	if statusCode < 100 || statusCode > 599 {
+		return "error"
+	}
+	return strconv.Itoa(statusCode/100) + "xx"
+}
+
+func result(ok bool, success, failure string) string {
+	if ok {
+		return success
+	}
+	return failure
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedPairs(m map[[2]string]uint64) [][2]string {
+	keys := make([][2]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
@@ -0,0 +1,58 @@
+package prometheus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jweckschmied/applemaps-go"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ applemaps.Metrics = (*Collector)(nil)
+
+func TestCollector(t *testing.T) {
+	c := NewCollector("applemaps", 0.1, 1)
+	c.ObserveRequest("token", 200, 50*time.Millisecond)
+	c.ObserveRequest("search", 200, 500*time.Millisecond)
+	c.ObserveRequest("search", 503, 2*time.Second)
+	c.ObserveRequest("search", 0, time.Millisecond)
+	c.ObserveRetry("search")
+	c.ObserveTokenRenewal(true)
+	c.ObserveCache("geocode", true)
+	c.ObserveCache("geocode", false)
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `# HELP applemaps_requests_total Number of requests sent to the Apple Maps Server API by endpoint and status class.
+# TYPE applemaps_requests_total counter
+applemaps_requests_total{endpoint="search",status_class="2xx"} 1
+applemaps_requests_total{endpoint="search",status_class="5xx"} 1
+applemaps_requests_total{endpoint="search",status_class="error"} 1
+applemaps_requests_total{endpoint="token",status_class="2xx"} 1
+# HELP applemaps_request_duration_seconds Latency of requests sent to the Apple Maps Server API by endpoint.
+# TYPE applemaps_request_duration_seconds histogram
+applemaps_request_duration_seconds_bucket{endpoint="search",le="0.1"} 1
+applemaps_request_duration_seconds_bucket{endpoint="search",le="1"} 2
+applemaps_request_duration_seconds_bucket{endpoint="search",le="+Inf"} 3
+applemaps_request_duration_seconds_sum{endpoint="search"} 2.501
+applemaps_request_duration_seconds_count{endpoint="search"} 3
+applemaps_request_duration_seconds_bucket{endpoint="token",le="0.1"} 1
+applemaps_request_duration_seconds_bucket{endpoint="token",le="1"} 1
+applemaps_request_duration_seconds_bucket{endpoint="token",le="+Inf"} 1
+applemaps_request_duration_seconds_sum{endpoint="token"} 0.05
+applemaps_request_duration_seconds_count{endpoint="token"} 1
+# HELP applemaps_retries_total Number of retried requests by endpoint.
+# TYPE applemaps_retries_total counter
+applemaps_retries_total{endpoint="search"} 1
+# HELP applemaps_cache_requests_total Number of cache lookups by endpoint and result.
+# TYPE applemaps_cache_requests_total counter
+applemaps_cache_requests_total{endpoint="geocode",result="hit"} 1
+applemaps_cache_requests_total{endpoint="geocode",result="miss"} 1
+# HELP applemaps_token_renewals_total Number of access token renewals by result.
+# TYPE applemaps_token_renewals_total counter
+applemaps_token_renewals_total{result="success"} 1
+`, rec.Body.String())
+}
@@ -0,0 +1,234 @@
+package applemaps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrThrottled is returned when a request exceeds the client-side rate limit and the limiter does not block.
+	ErrThrottled = errors.New("client-side rate limit exceeded")
+	// ErrQuotaExceeded is returned when the daily quota configured for the client has been used up
+	// and the limiter does not block.
+	ErrQuotaExceeded = errors.New("daily quota exceeded")
+)
+
+// LimitError is returned when a request was not sent because of the client-side rate limit or daily quota.
+// It wraps either ErrThrottled or ErrQuotaExceeded.
+type LimitError struct {
+	// Endpoint is the API endpoint of the rejected request.
+	Endpoint string
+	// RetryAfter is the time until the request could be sent.
+	RetryAfter time.Duration
+	// Err is either ErrThrottled or ErrQuotaExceeded.
+	Err error
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s: %s, retry after %s", e.Err, e.Endpoint, e.RetryAfter)
+}
+
+func (e *LimitError) Unwrap() error {
+	return e.Err
+}
+
+// RateLimit describes a token bucket that allows Rate requests per second on average, with bursts of up to Burst requests.
+// A Rate of zero disables the limit.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitConfig configures the client-side rate limiter and quota tracker.
+type RateLimitConfig struct {
+	// Global limits the requests to all endpoints combined.
+	Global RateLimit
+	// Endpoints limits the requests to single endpoints, keyed by endpoint name, for example "geocode" or "search".
+	Endpoints map[string]RateLimit
+	// DailyQuota is the number of calls allowed per UTC day across all endpoints. Zero disables the quota.
+	DailyQuota int
+	// Block makes requests wait until the rate limit or quota allows them, or until their context is done.
+	// Otherwise, requests fail immediately with a *LimitError.
+	Block bool
+}
+
+// Usage describes the API calls made during the current UTC day.
+type Usage struct {
+	// Day is the start of the UTC day the usage was counted for.
+	Day time.Time
+	// Calls contains the number of calls per endpoint.
+	Calls map[string]int
+	// Total is the number of calls to all endpoints.
+	Total int
+	// Quota is the configured daily quota, or zero if there is none.
+	Quota int
+}
+
+// Remaining returns the number of calls left for the current day, or -1 if there is no quota.
+func (u Usage) Remaining() int {
+	if u.Quota == 0 {
+		return -1
+	}
+	if u.Total >= u.Quota {
+		return 0
+	}
+	return u.Quota - u.Total
+}
+
+// WithRateLimit returns a functional ClientOption used to limit the rate of requests and track the calls made per day
+// when creating a new Apple Maps API Client using NewAppleMaps().
+// Every attempt of a request, including retries, counts against the limits. Access token requests are not counted.
+func WithRateLimit(config RateLimitConfig) ClientOption {
+	return func(c *client) {
+		c.limiter = newLimiter(config, time.Now)
+	}
+}
+
+type (
+	// limiter enforces a RateLimitConfig and counts the calls per endpoint.
+	limiter struct {
+		mu        sync.Mutex
+		global    *bucket
+		endpoints map[string]*bucket
+		quota     int
+		block     bool
+		day       time.Time
+		calls     map[string]int
+		total     int
+		now       func() time.Time
+	}
+
+	// bucket is a token bucket refilled continuously at rate tokens per second.
+	bucket struct {
+		rate   float64
+		burst  float64
+		tokens float64
+		last   time.Time
+	}
+)
+
+func newLimiter(config RateLimitConfig, now func() time.Time) *limiter {
+	l := &limiter{
+		global:    newBucket(config.Global, now()),
+		endpoints: make(map[string]*bucket, len(config.Endpoints)),
+		quota:     config.DailyQuota,
+		block:     config.Block,
+		calls:     make(map[string]int),
+		now:       now,
+	}
+	for endpoint, limit := range config.Endpoints {
+		l.endpoints[endpoint] = newBucket(limit, now())
+	}
+	return l
+}
+
+func newBucket(limit RateLimit, now time.Time) *bucket {
+	if limit.Rate <= 0 {
+		return nil
+	}
+	burst := math.Max(float64(limit.Burst), 1)
+	return &bucket{rate: limit.Rate, burst: burst, tokens: burst, last: now}
+}
+
+// wait returns the time until the bucket holds a full token, after refilling it up to now.
+// A nil bucket never has to wait.
+func (b *bucket) wait(now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+func (b *bucket) take() {
+	if b != nil {
+		b.tokens--
+	}
+}
+
+// acquire waits until a call to the endpoint is allowed by the rate limits and the daily quota, and counts it.
+// If the limiter does not block, a *LimitError is returned instead of waiting.
+func (l *limiter) acquire(ctx context.Context, endpoint string) error {
+	for {
+		d, err := l.tryAcquire(endpoint)
+		if d == 0 {
+			return nil
+		}
+		if !l.block {
+			return err
+		}
+		if err := sleep(ctx, d); err != nil {
+			return err
+		}
+	}
+}
+
+// tryAcquire counts a call to the endpoint if it is allowed right now.
+// Otherwise, it returns the time to wait before trying again, along with the matching *LimitError.
+func (l *limiter) tryAcquire(endpoint string) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.resetDay(now)
+	if l.quota > 0 && l.total >= l.quota {
+		d := l.day.AddDate(0, 0, 1).Sub(now)
+		return d, &LimitError{Endpoint: endpoint, RetryAfter: d, Err: ErrQuotaExceeded}
+	}
+
+	endpointBucket := l.endpoints[endpoint]
+	d := l.global.wait(now)
+	if e := endpointBucket.wait(now); e > d {
+		d = e
+	}
+	if d > 0 {
+		return d, &LimitError{Endpoint: endpoint, RetryAfter: d, Err: ErrThrottled}
+	}
+
+	l.global.take()
+	endpointBucket.take()
+	l.calls[endpoint]++
+	l.total++
+	return 0, nil
+}
+
+// resetDay resets the call counts if a new UTC day has started. l.mu must be held by the caller.
+func (l *limiter) resetDay(now time.Time) {
+	day := now.UTC().Truncate(24 * time.Hour)
+	if day.Equal(l.day) {
+		return
+	}
+	l.day = day
+	l.calls = make(map[string]int)
+	l.total = 0
+}
+
+// usage returns a snapshot of the calls made during the current UTC day.
+func (l *limiter) usage() Usage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.resetDay(l.now())
+	calls := make(map[string]int, len(l.calls))
+	for endpoint, n := range l.calls {
+		calls[endpoint] = n
+	}
+	return Usage{Day: l.day, Calls: calls, Total: l.total, Quota: l.quota}
+}
+
+// Usage returns the API calls made during the current UTC day.
+// Calls are only counted if the client was created with the WithRateLimit() option, otherwise the usage is empty.
+func (c *client) Usage() Usage {
+	if c.limiter == nil {
+		return Usage{Calls: map[string]int{}}
+	}
+	return c.limiter.usage()
+}
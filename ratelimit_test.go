@@ -0,0 +1,103 @@
+package applemaps
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Throttle(t *testing.T) {
+	now := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	l := newLimiter(RateLimitConfig{
+		Global:    RateLimit{Rate: 10, Burst: 2},
+		Endpoints: map[string]RateLimit{searchEndpoint: {Rate: 1, Burst: 1}},
+	}, func() time.Time { return now })
+
+	assert.NoError(t, l.acquire(context.Background(), searchEndpoint))
+	err := l.acquire(context.Background(), searchEndpoint)
+	assert.ErrorIs(t, err, ErrThrottled)
+
+	var limitErr *LimitError
+	assert.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, searchEndpoint, limitErr.Endpoint)
+	assert.Equal(t, time.Second, limitErr.RetryAfter)
+
+	assert.NoError(t, l.acquire(context.Background(), geocodeEndpoint))
+	assert.ErrorIs(t, l.acquire(context.Background(), geocodeEndpoint), ErrThrottled)
+
+	now = now.Add(time.Second)
+	assert.NoError(t, l.acquire(context.Background(), searchEndpoint))
+}
+
+func TestLimiter_Quota(t *testing.T) {
+	now := time.Date(2023, 7, 1, 23, 0, 0, 0, time.UTC)
+	l := newLimiter(RateLimitConfig{DailyQuota: 2}, func() time.Time { return now })
+
+	assert.NoError(t, l.acquire(context.Background(), searchEndpoint))
+	assert.NoError(t, l.acquire(context.Background(), geocodeEndpoint))
+	err := l.acquire(context.Background(), searchEndpoint)
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+
+	var limitErr *LimitError
+	assert.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, time.Hour, limitErr.RetryAfter)
+
+	usage := l.usage()
+	assert.Equal(t, time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), usage.Day)
+	assert.Equal(t, map[string]int{searchEndpoint: 1, geocodeEndpoint: 1}, usage.Calls)
+	assert.Equal(t, 2, usage.Total)
+	assert.Equal(t, 0, usage.Remaining())
+
+	now = now.Add(time.Hour)
+	assert.NoError(t, l.acquire(context.Background(), searchEndpoint))
+	assert.Equal(t, 1, l.usage().Remaining())
+}
+
+func TestLimiter_Block(t *testing.T) {
+	l := newLimiter(RateLimitConfig{Global: RateLimit{Rate: 100, Burst: 1}, Block: true}, time.Now)
+
+	start := time.Now()
+	assert.NoError(t, l.acquire(context.Background(), searchEndpoint))
+	assert.NoError(t, l.acquire(context.Background(), searchEndpoint))
+	assert.GreaterOrEqual(t, time.Since(start), 5*time.Millisecond)
+
+	l = newLimiter(RateLimitConfig{Global: RateLimit{Rate: 0.01, Burst: 1}, Block: true}, time.Now)
+	assert.NoError(t, l.acquire(context.Background(), searchEndpoint))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, l.acquire(ctx, searchEndpoint), context.DeadlineExceeded)
+}
+
+func TestWithRateLimit(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/geocode", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(geocode_SuccessResponse))
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL), WithRateLimit(RateLimitConfig{DailyQuota: 1}))
+	_, err := mapsClient.Geocode(context.Background(), "test query")
+	assert.NoError(t, err)
+	_, err = mapsClient.Geocode(context.Background(), "test query")
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+
+	usage := mapsClient.Usage()
+	assert.Equal(t, map[string]int{geocodeEndpoint: 1}, usage.Calls)
+	assert.Equal(t, 1, usage.Quota)
+}
+
+func TestUsage_NoLimiter(t *testing.T) {
+	mapsClient := NewAppleMaps(http.DefaultClient, "jwt")
+	assert.Equal(t, 0, mapsClient.Usage().Total)
+	assert.Equal(t, -1, mapsClient.Usage().Remaining())
+}
@@ -0,0 +1,105 @@
+package applemaps
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// defaultMaxResponseSize is the default maximum size of a response body in bytes.
+const defaultMaxResponseSize = 10 << 20
+
+// WithMaxResponseSize returns a functional ClientOption used to set the maximum size of a response body in bytes
+// when creating a new Apple Maps API Client using NewAppleMaps(). Larger responses fail with ErrResponseTooLarge.
+// The limit applies to the decompressed body. Defaults to 10 MiB.
+func WithMaxResponseSize(size int64) ClientOption {
+	return func(c *client) {
+		c.maxResponseSize = size
+	}
+}
+
+// send is the innermost Handler, sending the request to the API.
+// The response body is always read completely and closed, so the returned Response holds the body in memory.
+// A successful response that is neither declared nor recognized as JSON fails with ErrUnexpectedResponse.
+func (c *client) send(ctx context.Context, r *Request) (*Response, error) {
+	path, err := url.JoinPath(c.baseURL, r.Path)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range r.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	if r.Values != nil {
+		req.URL.RawQuery = r.Values.Encode()
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.readBody(res)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s response: %w", r.Endpoint, err)
+	}
+
+	response := &Response{StatusCode: res.StatusCode, Header: res.Header, Body: bytes.NewReader(body)}
+	if res.StatusCode != http.StatusOK {
+		return response, newAPIError(r.Endpoint, res, body)
+	}
+	if !isJSON(res.Header.Get("Content-Type")) && !json.Valid(body) {
+		return response, fmt.Errorf("%w from %s: %s", ErrUnexpectedResponse, r.Endpoint, describeBody(res.Header.Get("Content-Type"), body))
+	}
+	return response, nil
+}
+
+// readBody reads the complete, decompressed body of the response up to the maximum response size, and closes it.
+func (c *client) readBody(res *http.Response) ([]byte, error) {
+	defer res.Body.Close()
+
+	maxSize := c.maxResponseSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxResponseSize
+	}
+
+	var reader io.Reader = res.Body
+	if strings.EqualFold(res.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	body, err := io.ReadAll(io.LimitReader(reader, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > maxSize {
+		return nil, fmt.Errorf("%w: exceeds %d bytes", ErrResponseTooLarge, maxSize)
+	}
+	return body, nil
+}
+
+// isJSON reports whether the given Content-Type header describes a JSON body.
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
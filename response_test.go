@@ -0,0 +1,151 @@
+package applemaps
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	proxyError_HTMLResponse string = `<html>
+<head><title>502 Bad Gateway</title></head>
+<body><h1>Bad Gateway</h1></body>
+</html>`
+)
+
+// closeTrackingTransport wraps the bodies of all responses to record whether they were closed.
+type closeTrackingTransport struct {
+	mu     sync.Mutex
+	bodies []*trackedBody
+	next   http.RoundTripper
+}
+
+type trackedBody struct {
+	io.ReadCloser
+	closed bool
+}
+
+func (b *trackedBody) Close() error {
+	b.closed = true
+	return b.ReadCloser.Close()
+}
+
+func (t *closeTrackingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body := &trackedBody{ReadCloser: res.Body}
+	res.Body = body
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.bodies = append(t.bodies, body)
+	return res, nil
+}
+
+func newResponseTestServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/geocode", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(geocode_SuccessResponse))
+	})
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(proxyError_HTMLResponse))
+	})
+	mux.HandleFunc("/searchAutocomplete", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(proxyError_HTMLResponse))
+	})
+	mux.HandleFunc("/reverseGeocode", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write([]byte(reverseGeocode_SuccessResponse))
+		gz.Close()
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusOK)
+		w.Write(buf.Bytes())
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestResponse_BodyClosed(t *testing.T) {
+	testServer := newResponseTestServer()
+	defer testServer.Close()
+
+	transport := &closeTrackingTransport{next: testServer.Client().Transport}
+	mapsClient := NewAppleMaps(&http.Client{Transport: transport}, "jwt", WithCustomURL(testServer.URL))
+	_, err := mapsClient.Geocode(context.Background(), "test query")
+	assert.NoError(t, err)
+	_, err = mapsClient.Search(context.Background(), "test query")
+	assert.Error(t, err)
+
+	assert.Len(t, transport.bodies, 3)
+	for _, body := range transport.bodies {
+		assert.True(t, body.closed)
+	}
+}
+
+func TestResponse_MaxSize(t *testing.T) {
+	testServer := newResponseTestServer()
+	defer testServer.Close()
+
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL), WithMaxResponseSize(int64(len(accessToken_SuccessResponse))))
+	_, err := mapsClient.Geocode(context.Background(), "test query")
+	assert.ErrorIs(t, err, ErrResponseTooLarge)
+}
+
+func TestResponse_HTMLError(t *testing.T) {
+	testServer := newResponseTestServer()
+	defer testServer.Close()
+
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL))
+	_, err := mapsClient.Search(context.Background(), "test query")
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	assert.Equal(t, "unexpected text/html response: <html> <head><title>502 Bad Gateway</title></head> <body><h1>Bad Gateway</h1></body> </html>", apiErr.Message)
+
+	_, err = mapsClient.SearchAutocomplete(context.Background(), "test query")
+	assert.ErrorIs(t, err, ErrUnexpectedResponse)
+	assert.True(t, strings.Contains(err.Error(), "502 Bad Gateway"))
+}
+
+func TestResponse_Gzip(t *testing.T) {
+	testServer := newResponseTestServer()
+	defer testServer.Close()
+
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL))
+	res, err := mapsClient.ReverseGeocode(context.Background(), NewLocation(1, 1))
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+}
+
+func TestIsJSON(t *testing.T) {
+	assert.True(t, isJSON("application/json"))
+	assert.True(t, isJSON("application/json;charset=utf8"))
+	assert.True(t, isJSON("application/problem+json"))
+	assert.False(t, isJSON("text/html"))
+	assert.False(t, isJSON(""))
+}
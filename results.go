@@ -1,8 +1,18 @@
 package applemaps
 
 type SearchResponse struct {
-	DisplayMapRegion MapRegion `json:"displayMapRegion"`
-	Results          []Place   `json:"results"`
+	DisplayMapRegion MapRegion      `json:"displayMapRegion"`
+	Results          []Place        `json:"results"`
+	PaginationInfo   PaginationInfo `json:"paginationInfo"`
+}
+
+type PaginationInfo struct {
+	// NextPageToken is the token to request the next page of results with WithPageToken(), empty on the last page.
+	NextPageToken string `json:"nextPageToken"`
+	// PrevPageToken is the token to request the previous page of results with WithPageToken(), empty on the first page.
+	PrevPageToken  string `json:"prevPageToken"`
+	TotalPageCount int    `json:"totalPageCount"`
+	TotalResults   int    `json:"totalResults"`
 }
 
 type SearchAutocompleteResult struct {
@@ -52,3 +62,24 @@ type Step struct {
 	StepPathIndex   int    `json:"stepPathIndex"`
 	TransportType   string `json:"transportType"`
 }
+
+type PlacesResponse struct {
+	Results []Place            `json:"results"`
+	Errors  []PlaceLookupError `json:"errors"`
+}
+
+type AlternateIDsResponse struct {
+	Results []AlternateIDs     `json:"results"`
+	Errors  []PlaceLookupError `json:"errors"`
+}
+
+type AlternateIDs struct {
+	ID           string   `json:"id"`
+	AlternateIDs []string `json:"alternateIds"`
+}
+
+type PlaceLookupError struct {
+	// ErrorCode is the reason the place could not be looked up, for example FAILED_INVALID_ID or FAILED_NOT_FOUND.
+	ErrorCode string `json:"errorCode"`
+	ID        string `json:"id"`
+}
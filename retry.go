@@ -0,0 +1,120 @@
+package applemaps
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy describes how failed requests are retried.
+// A request is retried if it failed with one of the RetryableStatusCodes, or with a network error
+// if RetryNetworkErrors is set, until MaxAttempts is reached or the request context is done.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts per request, including the first one.
+	// A value of 1 or less disables retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry. It doubles with every further attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between two attempts. It does not apply to delays requested via the Retry-After header,
+	// which are limited by MaxRetryAfter instead.
+	MaxDelay time.Duration
+	// MaxRetryAfter is the longest delay requested via the Retry-After header that is waited for. If the server asks
+	// to wait longer, for example because the daily quota is exhausted, the request fails with the *APIError instead.
+	// Zero means no limit.
+	MaxRetryAfter time.Duration
+	// Jitter is the fraction (between 0 and 1) of each delay that is randomized, to avoid retrying in lockstep.
+	Jitter float64
+	// RetryableStatusCodes lists the HTTP status codes that are retried.
+	RetryableStatusCodes []int
+	// RetryNetworkErrors enables retries for requests that failed without receiving a response.
+	RetryNetworkErrors bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy that retries rate limited requests, server errors and network errors
+// up to three times with exponential backoff, waiting at most one minute if the server sends a Retry-After header.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:   4,
+		BaseDelay:     500 * time.Millisecond,
+		MaxDelay:      10 * time.Second,
+		MaxRetryAfter: time.Minute,
+		Jitter:        0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryNetworkErrors: true,
+	}
+}
+
+// WithRetryPolicy returns a functional ClientOption used to enable retries of failed requests
+// when creating a new Apple Maps API Client using NewAppleMaps().
+// Independent of the retry policy, a request rejected as unauthorized discards the cached access token and is attempted
+// once more with a new one, in case the token was revoked before its expiry.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *client) {
+		c.retryPolicy = policy
+	}
+}
+
+// isRetryable reports whether a request that failed with err should be attempted again.
+func (p RetryPolicy) isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if p.MaxRetryAfter > 0 && apiErr.RetryAfter > p.MaxRetryAfter {
+			return false
+		}
+		for _, code := range p.RetryableStatusCodes {
+			if apiErr.StatusCode == code {
+				return true
+			}
+		}
+		return false
+	}
+	var netErr net.Error
+	return p.RetryNetworkErrors && errors.As(err, &netErr)
+}
+
+// delay returns the time to wait before the next attempt, given the number of the failed attempt and its error.
+// A Retry-After duration sent by the server takes precedence over the backoff if it is longer.
+func (p RetryPolicy) delay(attempt int, err error) time.Duration {
+	backoff := float64(p.BaseDelay) * math.Pow(2, float64(attempt-1))
+	if p.MaxDelay > 0 && backoff > float64(p.MaxDelay) {
+		backoff = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		backoff -= backoff * math.Min(p.Jitter, 1) * rand.Float64()
+	}
+	d := time.Duration(backoff)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > d {
+		d = apiErr.RetryAfter
+	}
+	return d
+}
+
+// sleep waits for the given duration, or returns the context error if the context is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
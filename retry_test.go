@@ -0,0 +1,239 @@
+package applemaps
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = 5 * time.Millisecond
+	return policy
+}
+
+func TestRetry_ServerError(t *testing.T) {
+	var calls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/geocode", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(geocode_SuccessResponse))
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL), WithRetryPolicy(testRetryPolicy()))
+	res, err := mapsClient.Geocode(context.Background(), "test query")
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&calls))
+}
+
+func TestRetry_MaxAttempts(t *testing.T) {
+	var calls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/geocode", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	policy := testRetryPolicy()
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL), WithRetryPolicy(policy))
+	_, err := mapsClient.Geocode(context.Background(), "test query")
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.EqualValues(t, policy.MaxAttempts, atomic.LoadInt32(&calls))
+}
+
+func TestRetry_NotRetryable(t *testing.T) {
+	var calls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/geocode", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(badRequest_ErrorResponse))
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL), WithRetryPolicy(testRetryPolicy()))
+	_, err := mapsClient.Geocode(context.Background(), "test query")
+	assert.ErrorIs(t, err, ErrBadRequest)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+func TestRetry_RevokedAccessToken(t *testing.T) {
+	var tokenCalls, geocodeCalls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&tokenCalls, 1)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/geocode", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&geocodeCalls, 1) == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(geocode_SuccessResponse))
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL), WithRetryPolicy(testRetryPolicy()))
+	_, err := mapsClient.Geocode(context.Background(), "test query")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&tokenCalls))
+	assert.EqualValues(t, 2, atomic.LoadInt32(&geocodeCalls))
+}
+
+func TestRetry_RevokedAccessToken_NoRetryPolicy(t *testing.T) {
+	var tokenCalls, geocodeCalls int32
+	var revoked int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&tokenCalls, 1)
+		atomic.StoreInt32(&revoked, 0)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/geocode", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&geocodeCalls, 1)
+		if atomic.LoadInt32(&revoked) == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(geocode_SuccessResponse))
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL))
+	_, err := mapsClient.Geocode(context.Background(), "test query")
+	assert.NoError(t, err)
+
+	// the token is revoked, the request is attempted again with a new token
+	atomic.StoreInt32(&revoked, 1)
+	_, err = mapsClient.Geocode(context.Background(), "test query")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&tokenCalls))
+	assert.EqualValues(t, 3, atomic.LoadInt32(&geocodeCalls))
+}
+
+func TestRetry_Unauthorized_InvalidatesToken(t *testing.T) {
+	var tokenCalls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&tokenCalls, 1)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/geocode", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL))
+	for i := 0; i < 3; i++ {
+		_, err := mapsClient.Geocode(context.Background(), "test query")
+		assert.ErrorIs(t, err, ErrUnauthorized)
+	}
+	// every request requests a new token, as the one of the previous request was rejected
+	assert.EqualValues(t, 6, atomic.LoadInt32(&tokenCalls))
+}
+
+func TestRetry_ContextCanceled(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/geocode", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL), WithRetryPolicy(testRetryPolicy()))
+	_, err := mapsClient.Geocode(ctx, "test query")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRetry_RetryAfterTooLong(t *testing.T) {
+	var calls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/geocode", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "7200")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL), WithRetryPolicy(testRetryPolicy()))
+	start := time.Now()
+	_, err := mapsClient.Geocode(context.Background(), "test query")
+	assert.Less(t, time.Since(start), time.Second)
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 2*time.Hour, apiErr.RetryAfter)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+func TestRetryPolicy_IsRetryable(t *testing.T) {
+	policy := testRetryPolicy()
+	assert.True(t, policy.isRetryable(&APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Minute}))
+	assert.False(t, policy.isRetryable(&APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Minute + time.Second}))
+
+	policy.MaxRetryAfter = 0
+	assert.True(t, policy.isRetryable(&APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Hour}))
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	assert.Equal(t, time.Second, policy.delay(1, errors.New("error")))
+	assert.Equal(t, 4*time.Second, policy.delay(3, errors.New("error")))
+	assert.Equal(t, 5*time.Second, policy.delay(10, errors.New("error")))
+	assert.Equal(t, time.Minute, policy.delay(1, &APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Minute}))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := policy.delay(1, errors.New("error"))
+		assert.GreaterOrEqual(t, d, 500*time.Millisecond)
+		assert.LessOrEqual(t, d, time.Second)
+	}
+}
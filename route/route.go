@@ -0,0 +1,139 @@
+// Package route optimizes the order in which a set of stops is visited, using the travel times
+// between all stops requested from the Apple Maps Server API.
+package route
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jweckschmied/applemaps-go"
+)
+
+// latenessPenalty is the cost of arriving one second after the end of a time window,
+// relative to one second or meter of travel.
+const latenessPenalty = 1000
+
+// Objective is the quantity minimized by Optimize.
+type Objective int
+
+const (
+	// TravelTime minimizes the expected travel time, including waiting for time windows to open.
+	TravelTime Objective = iota
+	// Distance minimizes the travelled distance.
+	Distance
+)
+
+type (
+	// Stop is a location to visit.
+	Stop struct {
+		Location applemaps.Location
+		// Earliest is the earliest arrival time at the stop, relative to the departure at the start.
+		// Arriving earlier means waiting until Earliest.
+		Earliest time.Duration
+		// Latest is the latest arrival time at the stop, relative to the departure at the start. Zero means no limit.
+		// Arriving later is allowed, but avoided as far as possible.
+		Latest time.Duration
+		// ServiceTime is the time spent at the stop.
+		ServiceTime time.Duration
+	}
+
+	// Problem describes the stops to visit.
+	Problem struct {
+		// Start is the location the route starts at.
+		Start applemaps.Location
+		// Stops are the locations to visit, in any order.
+		Stops []Stop
+		// End is the location the route ends at. If it is nil, the route ends at the last stop.
+		End *applemaps.Location
+	}
+
+	// Options configures Optimize.
+	Options struct {
+		// Objective is the quantity to minimize. Defaults to TravelTime.
+		Objective Objective
+		// Concurrency is the maximum number of concurrent requests. Defaults to 4.
+		Concurrency int
+		// Directions enables requesting the directions of every leg of the optimized route.
+		Directions bool
+		// RequestOptions are passed to all Etas and Directions requests, for example WithTransportType().
+		RequestOptions []applemaps.RequestOption
+	}
+
+	// Plan is an optimized route.
+	Plan struct {
+		// Order contains the indexes of the stops of the Problem in the order they are visited.
+		Order []int
+		// Legs contains the legs of the route, from the start to the first stop, between the stops,
+		// and from the last stop to the end if there is one.
+		Legs []Leg
+		// DistanceMeters is the total distance of the route.
+		DistanceMeters int
+		// Duration is the total duration of the route, including waiting and service times.
+		Duration time.Duration
+		// Late contains the indexes of the stops reached after the end of their time window.
+		Late []int
+	}
+
+	// Leg is a part of the route between two consecutive locations.
+	Leg struct {
+		From applemaps.Location
+		To   applemaps.Location
+		// Stop is the index of the stop at the end of the leg in the Problem, or -1 for the end of the route.
+		Stop           int
+		DistanceMeters int
+		TravelTime     time.Duration
+		// Arrival is the arrival time at the end of the leg, relative to the departure at the start.
+		Arrival time.Duration
+		// Directions are the directions of the leg, if requested using Options.Directions.
+		Directions *applemaps.DirectionsResponse
+	}
+)
+
+// Optimize returns the order to visit the stops in, which minimizes the objective while respecting the time windows
+// of the stops as far as possible. The travel times between all locations are requested using applemaps.Matrix.
+// The order is found using the nearest neighbour heuristic, improved by 2-opt and or-opt moves.
+// This finds good routes for up to a few dozen stops, but not necessarily the best one.
+func Optimize(ctx context.Context, c applemaps.Client, problem Problem, options Options) (*Plan, error) {
+	if len(problem.Stops) == 0 {
+		return nil, errors.New("stops cannot be empty")
+	}
+
+	locations := problem.locations()
+	matrix, err := applemaps.Matrix(ctx, c, locations, locations, applemaps.MatrixOptions{Concurrency: options.Concurrency}, options.RequestOptions...)
+	if err != nil {
+		return nil, err
+	}
+	s, err := newSolver(problem, matrix, options.Objective)
+	if err != nil {
+		return nil, err
+	}
+
+	order := s.improve(s.nearestNeighbour())
+	plan := s.plan(order)
+	if options.Directions {
+		for i := range plan.Legs {
+			leg := &plan.Legs[i]
+			leg.Directions, err = c.DirectionsBetween(ctx, applemaps.NewLocationWaypoint(leg.From), applemaps.NewLocationWaypoint(leg.To),
+				options.RequestOptions...)
+			if err != nil {
+				return plan, fmt.Errorf("directions of leg %d: %w", i, err)
+			}
+		}
+	}
+	return plan, nil
+}
+
+// locations returns the start, the stops and the end, if there is one.
+func (p Problem) locations() []applemaps.Location {
+	locations := make([]applemaps.Location, 0, len(p.Stops)+2)
+	locations = append(locations, p.Start)
+	for _, stop := range p.Stops {
+		locations = append(locations, stop.Location)
+	}
+	if p.End != nil {
+		locations = append(locations, *p.End)
+	}
+	return locations
+}
@@ -0,0 +1,158 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jweckschmied/applemaps-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// euclideanMatrix returns a matrix with a distance of one meter and a travel time of one second
+// per unit of euclidean distance between the locations.
+func euclideanMatrix(locations []applemaps.Location) *applemaps.MatrixResponse {
+	m := &applemaps.MatrixResponse{Origins: locations, Destinations: locations, Etas: make([][]*applemaps.Eta, len(locations))}
+	for i, from := range locations {
+		m.Etas[i] = make([]*applemaps.Eta, len(locations))
+		for j, to := range locations {
+			d := int(math.Round(math.Hypot(from.Latitude-to.Latitude, from.Longitude-to.Longitude)))
+			m.Etas[i][j] = &applemaps.Eta{Destination: to, DistanceMeters: d, ExpectedTravelTimeSeconds: d}
+		}
+	}
+	return m
+}
+
+func newTestSolver(t *testing.T, problem Problem, objective Objective) *solver {
+	s, err := newSolver(problem, euclideanMatrix(problem.locations()), objective)
+	assert.NoError(t, err)
+	return s
+}
+
+func TestSolver_Line(t *testing.T) {
+	// stops on a line, given in a shuffled order
+	problem := Problem{Start: applemaps.NewLocation(0, 0)}
+	for _, x := range []float64{30, 10, 50, 20, 40} {
+		problem.Stops = append(problem.Stops, Stop{Location: applemaps.NewLocation(x, 0)})
+	}
+	s := newTestSolver(t, problem, Distance)
+	order := s.improve(s.nearestNeighbour())
+	assert.Equal(t, []int{1, 3, 0, 4, 2}, order)
+	assert.Equal(t, 50, s.plan(order).DistanceMeters)
+}
+
+func TestSolver_Improve(t *testing.T) {
+	// stops on a circle around the start and end, where the nearest neighbour heuristic leaves a detour
+	end := applemaps.NewLocation(0, 0)
+	problem := Problem{Start: applemaps.NewLocation(0, 0), End: &end}
+	for i := 0; i < 12; i++ {
+		angle := float64(i) * 2 * math.Pi / 12
+		problem.Stops = append(problem.Stops, Stop{Location: applemaps.NewLocation(100*math.Cos(angle), 100*math.Sin(angle))})
+	}
+	problem.Stops[5], problem.Stops[9] = problem.Stops[9], problem.Stops[5]
+
+	s := newTestSolver(t, problem, Distance)
+	initial := s.nearestNeighbour()
+	improved := s.improve(initial)
+	assert.LessOrEqual(t, s.cost(improved), s.cost(initial))
+
+	// the best route visits the stops around the circle: 100 + 11 * 52 + 100
+	assert.Equal(t, 772, s.plan(improved).DistanceMeters)
+}
+
+func TestSolver_TimeWindows(t *testing.T) {
+	// the farther stop has to be visited first to arrive within its time window
+	problem := Problem{
+		Start: applemaps.NewLocation(0, 0),
+		Stops: []Stop{
+			{Location: applemaps.NewLocation(10, 0), ServiceTime: 10 * time.Second},
+			{Location: applemaps.NewLocation(20, 0), Latest: 25 * time.Second},
+		},
+	}
+	s := newTestSolver(t, problem, TravelTime)
+	order := s.improve(s.nearestNeighbour())
+	assert.Equal(t, []int{1, 0}, order)
+
+	plan := s.plan(order)
+	assert.Empty(t, plan.Late)
+	assert.Equal(t, 20*time.Second, plan.Legs[0].Arrival)
+	assert.Equal(t, 30*time.Second, plan.Legs[1].Arrival)
+	assert.Equal(t, 40*time.Second, plan.Duration)
+
+	plan = s.plan([]int{0, 1})
+	assert.Equal(t, []int{1}, plan.Late)
+}
+
+func TestSolver_Earliest(t *testing.T) {
+	problem := Problem{
+		Start: applemaps.NewLocation(0, 0),
+		Stops: []Stop{{Location: applemaps.NewLocation(10, 0), Earliest: time.Minute}},
+	}
+	s := newTestSolver(t, problem, TravelTime)
+	plan := s.plan([]int{0})
+	assert.Equal(t, 10*time.Second, plan.Legs[0].Arrival)
+	assert.Equal(t, time.Minute, plan.Duration)
+}
+
+func TestMoveSegment(t *testing.T) {
+	src := []int{0, 1, 2, 3, 4}
+	dst := make([]int, len(src))
+	moveSegment(dst, src, 1, 2, 3)
+	assert.Equal(t, []int{0, 3, 4, 1, 2}, dst)
+	moveSegment(dst, src, 3, 1, 0)
+	assert.Equal(t, []int{3, 0, 1, 2, 4}, dst)
+}
+
+func TestOptimize(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"accessToken":"thisis.thejwt.token","expiresInSeconds":1800}`))
+	})
+	mux.HandleFunc("/etas", func(w http.ResponseWriter, r *http.Request) {
+		origin := parseLocation(r.URL.Query().Get("origin"))
+		var locations []applemaps.Location
+		for _, d := range strings.Split(r.URL.Query().Get("destinations"), "|") {
+			locations = append(locations, parseLocation(d))
+		}
+		m := euclideanMatrix(append([]applemaps.Location{origin}, locations...))
+		var res applemaps.EtaResponse
+		for _, eta := range m.Etas[0][1:] {
+			res.Etas = append(res.Etas, *eta)
+		}
+		json.NewEncoder(w).Encode(res)
+	})
+	mux.HandleFunc("/directions", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(applemaps.DirectionsResponse{Routes: []applemaps.Route{{Name: r.URL.Query().Get("destination")}}})
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	mapsClient := applemaps.NewAppleMaps(testServer.Client(), "jwt", applemaps.WithCustomURL(testServer.URL))
+	problem := Problem{Start: applemaps.NewLocation(0, 0)}
+	for _, x := range []float64{30, 10, 20} {
+		problem.Stops = append(problem.Stops, Stop{Location: applemaps.NewLocation(x, 0)})
+	}
+	plan, err := Optimize(context.Background(), mapsClient, problem, Options{Directions: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 0}, plan.Order)
+	assert.Equal(t, 30*time.Second, plan.Duration)
+	assert.Len(t, plan.Legs, 3)
+	assert.Equal(t, "30,0", plan.Legs[2].Directions.Routes[0].Name)
+
+	_, err = Optimize(context.Background(), mapsClient, Problem{}, Options{})
+	assert.Error(t, err)
+}
+
+func parseLocation(s string) applemaps.Location {
+	parts := strings.Split(s, ",")
+	lat, _ := strconv.ParseFloat(parts[0], 64)
+	lng, _ := strconv.ParseFloat(parts[1], 64)
+	return applemaps.NewLocation(lat, lng)
+}
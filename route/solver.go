@@ -0,0 +1,219 @@
+package route
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jweckschmied/applemaps-go"
+)
+
+// maxImprovementPasses bounds the number of passes over all moves during the local search.
+const maxImprovementPasses = 1000
+
+// solver finds a good visiting order for the stops of a problem. The nodes of the problem are the start (0),
+// the stops (1 to n) and the end (n+1), if there is one. Orders contain the indexes of the stops, not the nodes.
+type solver struct {
+	stops     []Stop
+	locations []applemaps.Location
+	hasEnd    bool
+	objective Objective
+	travel    [][]time.Duration
+	distance  [][]int
+}
+
+func newSolver(problem Problem, matrix *applemaps.MatrixResponse, objective Objective) (*solver, error) {
+	s := &solver{
+		stops:     problem.Stops,
+		locations: problem.locations(),
+		hasEnd:    problem.End != nil,
+		objective: objective,
+	}
+	nodes := len(s.locations)
+	s.travel = make([][]time.Duration, nodes)
+	s.distance = make([][]int, nodes)
+	for i := 0; i < nodes; i++ {
+		s.travel[i] = make([]time.Duration, nodes)
+		s.distance[i] = make([]int, nodes)
+		for j := 0; j < nodes; j++ {
+			if i == j {
+				continue
+			}
+			eta := matrix.At(i, j)
+			if eta == nil {
+				return nil, fmt.Errorf("no ETA from %s to %s", s.locations[i], s.locations[j])
+			}
+			s.travel[i][j] = time.Duration(eta.ExpectedTravelTimeSeconds) * time.Second
+			s.distance[i][j] = eta.DistanceMeters
+		}
+	}
+	return s, nil
+}
+
+// endNode returns the node of the end of the route, or -1 if the route ends at the last stop.
+func (s *solver) endNode() int {
+	if s.hasEnd {
+		return len(s.locations) - 1
+	}
+	return -1
+}
+
+// arc returns the cost of travelling directly between two nodes according to the objective.
+func (s *solver) arc(from, to int) float64 {
+	if s.objective == Distance {
+		return float64(s.distance[from][to])
+	}
+	return s.travel[from][to].Seconds()
+}
+
+// cost returns the cost of visiting the stops in the given order, including the penalty for late arrivals.
+func (s *solver) cost(order []int) float64 {
+	var elapsed, late time.Duration
+	var distance int
+	prev := 0
+	for _, stop := range order {
+		node := stop + 1
+		elapsed, late = s.visit(elapsed, late, prev, stop)
+		distance += s.distance[prev][node]
+		prev = node
+	}
+	if end := s.endNode(); end >= 0 {
+		elapsed += s.travel[prev][end]
+		distance += s.distance[prev][end]
+	}
+
+	cost := elapsed.Seconds()
+	if s.objective == Distance {
+		cost = float64(distance)
+	}
+	return cost + latenessPenalty*late.Seconds()
+}
+
+// visit travels from the node prev to the given stop, and returns the time elapsed after leaving the stop again
+// and the total lateness so far.
+func (s *solver) visit(elapsed, late time.Duration, prev, stop int) (time.Duration, time.Duration) {
+	elapsed += s.travel[prev][stop+1]
+	if elapsed < s.stops[stop].Earliest {
+		elapsed = s.stops[stop].Earliest
+	}
+	if latest := s.stops[stop].Latest; latest > 0 && elapsed > latest {
+		late += elapsed - latest
+	}
+	return elapsed + s.stops[stop].ServiceTime, late
+}
+
+// nearestNeighbour returns an order that always continues with the closest stop not visited yet.
+func (s *solver) nearestNeighbour() []int {
+	visited := make([]bool, len(s.stops))
+	order := make([]int, 0, len(s.stops))
+	prev := 0
+	for len(order) < len(s.stops) {
+		next := -1
+		for stop := range s.stops {
+			if !visited[stop] && (next < 0 || s.arc(prev, stop+1) < s.arc(prev, next+1)) {
+				next = stop
+			}
+		}
+		visited[next] = true
+		order = append(order, next)
+		prev = next + 1
+	}
+	return order
+}
+
+// improve applies 2-opt and or-opt moves to the order as long as they reduce its cost.
+func (s *solver) improve(order []int) []int {
+	best := s.cost(order)
+	candidate := make([]int, len(order))
+	for pass := 0; pass < maxImprovementPasses; pass++ {
+		improved := false
+
+		// 2-opt: reverse the segment between i and j
+		for i := 0; i < len(order)-1; i++ {
+			for j := i + 1; j < len(order); j++ {
+				copy(candidate, order)
+				reverse(candidate[i : j+1])
+				if c := s.cost(candidate); c < best {
+					order, candidate, best, improved = candidate, order, c, true
+				}
+			}
+		}
+
+		// or-opt: move a segment of up to three stops to another position
+		for length := 1; length <= 3 && length < len(order); length++ {
+			for i := 0; i+length <= len(order); i++ {
+				for k := 0; k <= len(order)-length; k++ {
+					if k == i {
+						continue
+					}
+					moveSegment(candidate, order, i, length, k)
+					if c := s.cost(candidate); c < best {
+						order, candidate, best, improved = candidate, order, c, true
+					}
+				}
+			}
+		}
+
+		if !improved {
+			break
+		}
+	}
+	return order
+}
+
+// plan builds the legs of the route for the given order.
+func (s *solver) plan(order []int) *Plan {
+	plan := &Plan{Order: order}
+	var elapsed, late time.Duration
+	prev := 0
+	for _, stop := range order {
+		node := stop + 1
+		leg := Leg{
+			From:           s.locations[prev],
+			To:             s.locations[node],
+			Stop:           stop,
+			DistanceMeters: s.distance[prev][node],
+			TravelTime:     s.travel[prev][node],
+			Arrival:        elapsed + s.travel[prev][node],
+		}
+		previousLate := late
+		elapsed, late = s.visit(elapsed, late, prev, stop)
+		if late > previousLate {
+			plan.Late = append(plan.Late, stop)
+		}
+		plan.Legs = append(plan.Legs, leg)
+		plan.DistanceMeters += leg.DistanceMeters
+		prev = node
+	}
+	if end := s.endNode(); end >= 0 {
+		leg := Leg{
+			From:           s.locations[prev],
+			To:             s.locations[end],
+			Stop:           -1,
+			DistanceMeters: s.distance[prev][end],
+			TravelTime:     s.travel[prev][end],
+			Arrival:        elapsed + s.travel[prev][end],
+		}
+		elapsed = leg.Arrival
+		plan.Legs = append(plan.Legs, leg)
+		plan.DistanceMeters += leg.DistanceMeters
+	}
+	plan.Duration = elapsed
+	return plan
+}
+
+func reverse(order []int) {
+	for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+		order[i], order[j] = order[j], order[i]
+	}
+}
+
+// moveSegment writes the order src to dst, with the segment of the given length starting at i moved
+// to start at position k of the result.
+func moveSegment(dst, src []int, i, length, k int) {
+	rest := make([]int, 0, len(src)-length)
+	rest = append(rest, src[:i]...)
+	rest = append(rest, src[i+length:]...)
+	n := copy(dst, rest[:k])
+	n += copy(dst[n:], src[i:i+length])
+	copy(dst[n:], rest[k:])
+}
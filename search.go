@@ -2,7 +2,10 @@ package applemaps
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/url"
+	"strings"
 )
 
 // Search performs a search to find places that match specific criteria.
@@ -24,3 +27,41 @@ func (c *client) SearchAutocomplete(ctx context.Context, query string, opts ...R
 	}
 	return exec[SearchAutocompleteResult](ctx, c, searchAutocompleteEndpoint, values)
 }
+
+// ResolveCompletion performs the search of an autocomplete result returned by SearchAutocomplete,
+// turning the suggestion into the places it describes.
+func (c *client) ResolveCompletion(ctx context.Context, result AutocompleteResult) (*SearchResponse, error) {
+	path, values, err := c.completionRequest(result)
+	if err != nil {
+		return nil, err
+	}
+	return execPath[SearchResponse](ctx, c, path, path, values)
+}
+
+// completionRequest splits the completion URL of an autocomplete result into the path relative to the base URL
+// and the query parameters. The completion URL contains the API version, for example "/v1/search?q=Coffee",
+// which is removed together with the path of the base URL.
+func (c *client) completionRequest(result AutocompleteResult) (string, url.Values, error) {
+	if result.CompletionUrl == "" {
+		return "", nil, errors.New("completion URL cannot be empty")
+	}
+	completion, err := url.Parse(result.CompletionUrl)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid completion URL: %w", err)
+	}
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		return "", nil, err
+	}
+
+	path := strings.TrimPrefix(completion.Path, "/")
+	if basePath := strings.Trim(base.Path, "/"); basePath != "" && strings.HasPrefix(path, basePath+"/") {
+		path = strings.TrimPrefix(path, basePath+"/")
+	} else {
+		path = strings.TrimPrefix(path, "v1/")
+	}
+	if path == "" {
+		return "", nil, fmt.Errorf("invalid completion URL: %s", result.CompletionUrl)
+	}
+	return path, completion.Query(), nil
+}
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -55,6 +56,44 @@ func (s *SearchTestSuite) TestSearchAutocomplete_Success() {
 	s.Equal(expected, res)
 }
 
+func (s *SearchTestSuite) TestResolveCompletion_Success() {
+	var autocomplete = &SearchAutocompleteResult{}
+	json.Unmarshal([]byte(searchAutocomplete_SuccessResponse), autocomplete)
+	var expected = &SearchResponse{}
+	json.Unmarshal([]byte(search_SuccessResponse), expected)
+
+	res, err := s.mapsClient.ResolveCompletion(context.Background(), autocomplete.Results[0])
+	s.NoError(err)
+	s.Equal(expected, res)
+
+	_, err = s.mapsClient.ResolveCompletion(context.Background(), AutocompleteResult{})
+	s.Error(err)
+}
+
+func TestCompletionRequest(t *testing.T) {
+	type test struct {
+		baseURL       string
+		completionURL string
+		path          string
+		query         string
+	}
+	tt := map[string]test{
+		"Default URL":       {apiBase, "/v1/search?q=Coffeeplace&metadata=abc", "search", "metadata=abc&q=Coffeeplace"},
+		"Custom URL":        {"http://localhost:8080", "/v1/search?q=Coffeeplace", "search", "q=Coffeeplace"},
+		"Custom URL Prefix": {"http://localhost:8080/maps/v1", "/maps/v1/search?q=Coffeeplace", "search", "q=Coffeeplace"},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			c := &client{baseURL: tc.baseURL}
+			path, values, err := c.completionRequest(AutocompleteResult{CompletionUrl: tc.completionURL})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.path, path)
+			assert.Equal(t, tc.query, values.Encode())
+		})
+	}
+}
+
 func TestSearchTestSuite(t *testing.T) {
 	suite.Run(t, new(SearchTestSuite))
 }
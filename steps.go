@@ -0,0 +1,118 @@
+package applemaps
+
+import (
+	"errors"
+	"fmt"
+)
+
+// IndexError describes an index of a DirectionsResponse that is out of range.
+type IndexError struct {
+	// Route is the index of the route the invalid index was found for.
+	Route int
+	// Step is the index of the step containing the invalid index, or -1 if the index is not a step path index.
+	Step int
+	// Field is the name of the field containing the index: "routes", "stepIndexes" or "stepPathIndex".
+	Field string
+	// Index is the invalid index and Length the length of the slice it refers to.
+	Index  int
+	Length int
+}
+
+func (e *IndexError) Error() string {
+	if e.Field == "routes" {
+		return fmt.Sprintf("route index %d out of range [0, %d)", e.Index, e.Length)
+	}
+	if e.Step < 0 {
+		return fmt.Sprintf("route %d: %s %d out of range [0, %d)", e.Route, e.Field, e.Index, e.Length)
+	}
+	return fmt.Sprintf("route %d, step %d: %s %d out of range [0, %d)", e.Route, e.Step, e.Field, e.Index, e.Length)
+}
+
+// ResolvedRoute is a route of a DirectionsResponse with its steps and their paths resolved.
+type ResolvedRoute struct {
+	Route
+	Steps []ResolvedStep
+}
+
+// ResolvedStep is a step of a route with its path resolved.
+type ResolvedStep struct {
+	Step
+	Path []Location
+}
+
+// RouteSteps returns the steps of the route with index i, in order.
+// It returns an *IndexError if i or any of the step indexes of the route are out of range.
+func (r *DirectionsResponse) RouteSteps(i int) ([]Step, error) {
+	resolved, err := r.resolveRoute(i)
+	if err != nil {
+		return nil, err
+	}
+	steps := make([]Step, len(resolved.Steps))
+	for j, step := range resolved.Steps {
+		steps[j] = step.Step
+	}
+	return steps, nil
+}
+
+// RoutePath returns the full path of the route with index i, joining the paths of all of its steps.
+// Consecutive identical points, such as the shared point between the paths of two steps, are included only once.
+// It returns an *IndexError if i or any of the step or step path indexes of the route are out of range.
+func (r *DirectionsResponse) RoutePath(i int) ([]Location, error) {
+	resolved, err := r.resolveRoute(i)
+	if err != nil {
+		return nil, err
+	}
+	var path []Location
+	for _, step := range resolved.Steps {
+		for _, l := range step.Path {
+			if len(path) == 0 || path[len(path)-1] != l {
+				path = append(path, l)
+			}
+		}
+	}
+	return path, nil
+}
+
+// ResolveRoutes returns all routes with their steps and step paths resolved.
+// If any index of the response is out of range, it returns an error joining an *IndexError for every invalid index,
+// along with the routes that could be resolved.
+func (r *DirectionsResponse) ResolveRoutes() ([]ResolvedRoute, error) {
+	routes := make([]ResolvedRoute, 0, len(r.Routes))
+	var errs []error
+	for i := range r.Routes {
+		resolved, err := r.resolveRoute(i)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		routes = append(routes, resolved)
+	}
+	return routes, errors.Join(errs...)
+}
+
+// resolveRoute resolves the steps and step paths of the route with index i.
+// The error joins an *IndexError for every invalid index of the route.
+func (r *DirectionsResponse) resolveRoute(i int) (ResolvedRoute, error) {
+	if i < 0 || i >= len(r.Routes) {
+		return ResolvedRoute{}, &IndexError{Route: i, Step: -1, Field: "routes", Index: i, Length: len(r.Routes)}
+	}
+	route := r.Routes[i]
+	resolved := ResolvedRoute{Route: route, Steps: make([]ResolvedStep, 0, len(route.StepIndexes))}
+	var errs []error
+	for _, s := range route.StepIndexes {
+		if s < 0 || s >= len(r.Steps) {
+			errs = append(errs, &IndexError{Route: i, Step: -1, Field: "stepIndexes", Index: s, Length: len(r.Steps)})
+			continue
+		}
+		step := r.Steps[s]
+		if step.StepPathIndex < 0 || step.StepPathIndex >= len(r.StepPaths) {
+			errs = append(errs, &IndexError{Route: i, Step: s, Field: "stepPathIndex", Index: step.StepPathIndex, Length: len(r.StepPaths)})
+			continue
+		}
+		resolved.Steps = append(resolved.Steps, ResolvedStep{Step: step, Path: r.StepPaths[step.StepPathIndex]})
+	}
+	if len(errs) > 0 {
+		return ResolvedRoute{}, errors.Join(errs...)
+	}
+	return resolved, nil
+}
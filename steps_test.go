@@ -0,0 +1,71 @@
+package applemaps
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDirectionsResponse_RouteSteps(t *testing.T) {
+	var res DirectionsResponse
+	assert.NoError(t, json.Unmarshal([]byte(directions_SuccessResponse), &res))
+
+	steps, err := res.RouteSteps(0)
+	assert.NoError(t, err)
+	assert.Equal(t, res.Steps, steps)
+
+	_, err = res.RouteSteps(1)
+	var indexErr *IndexError
+	assert.True(t, errors.As(err, &indexErr))
+	assert.Equal(t, "routes", indexErr.Field)
+	assert.Equal(t, 1, indexErr.Length)
+}
+
+func TestDirectionsResponse_RoutePath(t *testing.T) {
+	var res DirectionsResponse
+	assert.NoError(t, json.Unmarshal([]byte(directions_SuccessResponse), &res))
+
+	path, err := res.RoutePath(0)
+	assert.NoError(t, err)
+	assert.Len(t, path, 19)
+	assert.Equal(t, NewLocation(51.042699, 13.735173), path[0])
+	assert.Equal(t, NewLocation(51.04141, 13.734339), path[len(path)-1])
+	for i := 1; i < len(path); i++ {
+		assert.NotEqual(t, path[i-1], path[i])
+	}
+
+	_, err = res.RoutePath(-1)
+	assert.Error(t, err)
+}
+
+func TestDirectionsResponse_ResolveRoutes(t *testing.T) {
+	res := DirectionsResponse{
+		Routes: []Route{
+			{Name: "valid", StepIndexes: []int{1, 0}},
+			{Name: "invalid", StepIndexes: []int{0, 5, 2}},
+		},
+		Steps: []Step{
+			{Instructions: "first", StepPathIndex: 0},
+			{Instructions: "second", StepPathIndex: 1},
+			{Instructions: "broken", StepPathIndex: 7},
+		},
+		StepPaths: [][]Location{{NewLocation(1, 1)}, {NewLocation(2, 2)}},
+	}
+
+	routes, err := res.ResolveRoutes()
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "valid", routes[0].Name)
+	assert.Equal(t, "second", routes[0].Steps[0].Instructions)
+	assert.Equal(t, []Location{NewLocation(1, 1)}, routes[0].Steps[1].Path)
+
+	assert.EqualError(t, err, "route 1: stepIndexes 5 out of range [0, 3)\nroute 1, step 2: stepPathIndex 7 out of range [0, 2)")
+	var indexErr *IndexError
+	assert.True(t, errors.As(err, &indexErr))
+	assert.Equal(t, 1, indexErr.Route)
+	assert.Equal(t, "stepIndexes", indexErr.Field)
+
+	_, err = res.RouteSteps(1)
+	assert.Error(t, err)
+}
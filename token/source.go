@@ -0,0 +1,77 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// defaultRenewBefore is the time before the expiry of a JWT at which Source generates a new one.
+const defaultRenewBefore = time.Minute
+
+// Source provides Apple MapKit-compliant JWTs generated with GenerateJWT, and generates a new token
+// shortly before the current one expires. It can be used as an applemaps.TokenSource and is safe for concurrent use.
+type Source struct {
+	key      []byte
+	keyID    string
+	teamID   string
+	lifetime time.Duration
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+	now    func() time.Time
+}
+
+// NewSource creates a new Source, given a pem key (usually the raw content of a `.p8` file),
+// the keyID (10-character key identifier), the teamID (10-character Apple Developer Team ID)
+// and the lifetime of each generated token.
+// The first token is generated right away, so an invalid key is reported here instead of on the first request.
+// The lifetime must be positive.
+func NewSource(key []byte, keyID string, teamID string, lifetime time.Duration) (*Source, error) {
+	if lifetime <= 0 {
+		return nil, fmt.Errorf("token lifetime must be positive, got %s", lifetime)
+	}
+	if _, err := jwt.ParseECPrivateKeyFromPEM(key); err != nil {
+		return nil, err
+	}
+	s := &Source{
+		key:      key,
+		keyID:    keyID,
+		teamID:   teamID,
+		lifetime: lifetime,
+		now:      time.Now,
+	}
+	if _, err := s.Token(context.Background()); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Token returns the current JWT, or generates a new one if the current token is about to expire.
+// A new token is generated if the current one expires within the next minute,
+// or within half of the token lifetime for lifetimes shorter than two minutes.
+func (s *Source) Token(_ context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	renewBefore := defaultRenewBefore
+	if s.lifetime < 2*renewBefore {
+		renewBefore = s.lifetime / 2
+	}
+	now := s.now()
+	if s.token != "" && now.Before(s.expiry.Add(-renewBefore)) {
+		return s.token, nil
+	}
+
+	expiry := now.Add(s.lifetime)
+	token, err := GenerateJWT(s.key, s.keyID, s.teamID, expiry)
+	if err != nil {
+		return "", err
+	}
+	s.token, s.expiry = token, expiry
+	return token, nil
+}
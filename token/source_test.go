@@ -0,0 +1,59 @@
+package token
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSource(t *testing.T) {
+	source, err := NewSource(key, "1234567890", "ABCD123456", time.Hour)
+	assert.NoError(t, err)
+
+	token, err := source.Token(context.Background())
+	assert.NoError(t, err)
+
+	ecdsaPrivateKey, _ := jwt.ParseECPrivateKeyFromPEM(key)
+	tok, err := jwt.ParseWithClaims(token, &jwt.StandardClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return ecdsaPrivateKey.Public(), nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "1234567890", tok.Header["kid"])
+	assert.Equal(t, "ABCD123456", tok.Claims.(*jwt.StandardClaims).Issuer)
+}
+
+func TestNewSource_Invalid(t *testing.T) {
+	_, err := NewSource(invalid, "1234567890", "ABCD123456", time.Hour)
+	assert.Error(t, err)
+
+	_, err = NewSource(key, "1234567890", "ABCD123456", 0)
+	assert.Error(t, err)
+	_, err = NewSource(key, "1234567890", "ABCD123456", -time.Hour)
+	assert.Error(t, err)
+}
+
+func TestSource_Renewal(t *testing.T) {
+	now := time.Now()
+	source, err := NewSource(key, "1234567890", "ABCD123456", time.Hour)
+	assert.NoError(t, err)
+	source.now = func() time.Time { return now }
+
+	first, err := source.Token(context.Background())
+	assert.NoError(t, err)
+
+	// still valid for more than a minute, so the token is reused
+	source.now = func() time.Time { return now.Add(58 * time.Minute) }
+	second, err := source.Token(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	// expires within the next minute, so a new token is generated
+	source.now = func() time.Time { return now.Add(59*time.Minute + time.Second) }
+	third, err := source.Token(context.Background())
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, third)
+	assert.Equal(t, now.Add(119*time.Minute+time.Second), source.expiry)
+}
@@ -0,0 +1,27 @@
+package applemaps
+
+import "context"
+
+// TokenSource provides the JWT Auth Tokens used to request access tokens from the API.
+// Implementations must be safe for concurrent use.
+// See token.Source for an implementation that generates the tokens from a private key.
+type TokenSource interface {
+	// Token returns a currently valid JWT Auth Token.
+	Token(ctx context.Context) (string, error)
+}
+
+// staticTokenSource is a TokenSource that always returns the same auth token.
+type staticTokenSource string
+
+func (s staticTokenSource) Token(_ context.Context) (string, error) {
+	return string(s), nil
+}
+
+// authTokenSource returns the TokenSource of the client, or the static auth token if there is none.
+// c.mu must be held by the caller.
+func (c *client) authTokenSource() TokenSource {
+	if c.tokenSource != nil {
+		return c.tokenSource
+	}
+	return staticTokenSource(c.authToken)
+}
@@ -0,0 +1,58 @@
+package applemaps
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingTokenSource struct {
+	calls int32
+	err   error
+}
+
+func (s *countingTokenSource) Token(_ context.Context) (string, error) {
+	atomic.AddInt32(&s.calls, 1)
+	return "generated-jwt", s.err
+}
+
+func TestTokenSource(t *testing.T) {
+	var authHeader string
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	}))
+	defer testServer.Close()
+
+	source := &countingTokenSource{}
+	mapsClient := NewAppleMapsWithTokenSource(testServer.Client(), source, WithCustomURL(testServer.URL)).(*client)
+	tok, err := mapsClient.getAccessToken(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "thisis.thejwt.token", tok)
+	assert.Equal(t, "Bearer generated-jwt", authHeader)
+	assert.EqualValues(t, 1, source.calls)
+
+	mapsClient.SetAuthToken("static-jwt")
+	_, err = mapsClient.getAccessToken(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer static-jwt", authHeader)
+	assert.EqualValues(t, 1, source.calls)
+}
+
+func TestTokenSource_Error(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request expected")
+	}))
+	defer testServer.Close()
+
+	source := &countingTokenSource{err: errors.New("invalid key")}
+	mapsClient := NewAppleMapsWithTokenSource(testServer.Client(), source, WithCustomURL(testServer.URL))
+	_, err := mapsClient.Geocode(context.Background(), "test query")
+	assert.EqualError(t, err, "invalid key")
+}
@@ -0,0 +1,92 @@
+package applemaps
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// locationParameters lists the query parameters set by RequestOptions that contain a Location.
+var locationParameters = []string{"searchLocation", "userLocation"}
+
+// regionParameters lists the query parameters set by RequestOptions that contain a MapRegion.
+var regionParameters = []string{"searchRegion"}
+
+// Validate returns an error wrapping ErrInvalidCoordinates if the latitude is not within [-90, 90]
+// or the longitude is not within [-180, 180].
+func (l Location) Validate() error {
+	if !validCoordinate(l.Latitude, 90) {
+		return fmt.Errorf("%w: latitude %v out of range [-90, 90]", ErrInvalidCoordinates, l.Latitude)
+	}
+	if !validCoordinate(l.Longitude, 180) {
+		return fmt.Errorf("%w: longitude %v out of range [-180, 180]", ErrInvalidCoordinates, l.Longitude)
+	}
+	return nil
+}
+
+// Validate returns an error wrapping ErrInvalidCoordinates if any latitude or longitude of the region
+// is out of range, or if its SouthLatitude is greater than its NorthLatitude.
+// A WestLongitude greater than the EastLongitude is valid, as the region crosses the antimeridian.
+func (r MapRegion) Validate() error {
+	if err := NewLocation(r.NorthLatitude, r.EastLongitude).Validate(); err != nil {
+		return err
+	}
+	if err := NewLocation(r.SouthLatitude, r.WestLongitude).Validate(); err != nil {
+		return err
+	}
+	if r.SouthLatitude > r.NorthLatitude {
+		return fmt.Errorf("%w: south latitude %v greater than north latitude %v", ErrInvalidCoordinates, r.SouthLatitude, r.NorthLatitude)
+	}
+	return nil
+}
+
+// validateParameters validates the locations and regions in the query parameters set by RequestOptions,
+// such as WithUserLocation() and WithSearchRegion().
+func validateParameters(values url.Values) error {
+	for _, name := range locationParameters {
+		for _, value := range values[name] {
+			coordinates, err := parseCoordinates(value, 2)
+			if err == nil {
+				err = NewLocation(coordinates[0], coordinates[1]).Validate()
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+	for _, name := range regionParameters {
+		for _, value := range values[name] {
+			coordinates, err := parseCoordinates(value, 4)
+			if err == nil {
+				err = NewRegion(coordinates[0], coordinates[1], coordinates[2], coordinates[3]).Validate()
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// parseCoordinates parses n comma separated numbers, as formatted by Location.String() and MapRegion.String().
+func parseCoordinates(value string, n int) ([]float64, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != n {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCoordinates, value)
+	}
+	coordinates := make([]float64, n)
+	for i, part := range parts {
+		var err error
+		if coordinates[i], err = strconv.ParseFloat(strings.TrimSpace(part), 64); err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidCoordinates, value)
+		}
+	}
+	return coordinates, nil
+}
+
+// validCoordinate returns whether the coordinate is a number within [-limit, limit].
+func validCoordinate(v, limit float64) bool {
+	return !math.IsNaN(v) && v >= -limit && v <= limit
+}
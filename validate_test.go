@@ -0,0 +1,82 @@
+package applemaps
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocation_Validate(t *testing.T) {
+	assert.NoError(t, NewLocation(0, 0).Validate())
+	assert.NoError(t, NewLocation(-90, 180).Validate())
+	assert.NoError(t, NewLocation(90, -180).Validate())
+
+	for _, l := range []Location{NewLocation(181, 0), NewLocation(-90.1, 0), NewLocation(0, 180.5), NewLocation(math.NaN(), 0), NewLocation(0, math.Inf(1))} {
+		assert.ErrorIs(t, l.Validate(), ErrInvalidCoordinates, l)
+	}
+}
+
+func TestMapRegion_Validate(t *testing.T) {
+	assert.NoError(t, NewRegion(10, 20, -10, -20).Validate())
+	assert.NoError(t, NewRegion(10, -170, -10, 170).Validate())
+
+	assert.ErrorIs(t, NewRegion(-10, 20, 10, -20).Validate(), ErrInvalidCoordinates)
+	assert.ErrorIs(t, NewRegion(91, 20, 10, -20).Validate(), ErrInvalidCoordinates)
+	assert.ErrorIs(t, NewRegion(10, 20, 10, -200).Validate(), ErrInvalidCoordinates)
+}
+
+func TestValidateParameters(t *testing.T) {
+	values := url.Values{}
+	WithUserLocation(NewLocation(1, 2))(values)
+	WithSearchRegion(NewRegion(10, 20, -10, -20))(values)
+	assert.NoError(t, validateParameters(values))
+
+	WithSearchLocation(NewLocation(181, 0))(values)
+	assert.ErrorIs(t, validateParameters(values), ErrInvalidCoordinates)
+
+	values = url.Values{}
+	WithSearchRegion(NewRegion(-10, 20, 10, -20))(values)
+	assert.ErrorContains(t, validateParameters(values), "searchRegion")
+
+	values = url.Values{"userLocation": {"abc"}}
+	assert.ErrorIs(t, validateParameters(values), ErrInvalidCoordinates)
+}
+
+func TestClient_ValidatesCoordinates(t *testing.T) {
+	var requests int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL))
+	ctx := context.Background()
+
+	_, err := mapsClient.ReverseGeocode(ctx, NewLocation(181, 0))
+	assert.ErrorIs(t, err, ErrInvalidCoordinates)
+	_, err = mapsClient.Etas(ctx, NewLocation(0, 0), []Location{NewLocation(1, 1), NewLocation(0, 200)})
+	assert.ErrorIs(t, err, ErrInvalidCoordinates)
+	assert.ErrorContains(t, err, "destination 1")
+	_, err = mapsClient.Search(ctx, "coffee", WithSearchRegion(NewRegion(-10, 20, 10, -20)))
+	assert.ErrorIs(t, err, ErrInvalidCoordinates)
+	_, err = mapsClient.DirectionsBetween(ctx, NewWaypoint("Dresden"), NewWaypoint("Berlin"), WithUserLocation(NewLocation(math.NaN(), 0)))
+	assert.ErrorIs(t, err, ErrInvalidCoordinates)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&requests))
+
+	_, err = mapsClient.Search(ctx, "coffee", WithSearchLocation(NewLocation(51, 13)))
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
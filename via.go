@@ -0,0 +1,117 @@
+package applemaps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MultiStopDirectionsResponse contains the directions of a route through several waypoints, as returned by DirectionsVia.
+type MultiStopDirectionsResponse struct {
+	// DirectionsResponse combines the directions of all legs. Its Origin is the origin of the first leg and its
+	// Destination the destination of the last leg. Routes contains a single route joining the route of every leg,
+	// with the total distance and duration. Steps and StepPaths contain the steps of all legs in order.
+	DirectionsResponse
+	// Legs contains one entry per pair of consecutive waypoints.
+	Legs []DirectionsLeg
+}
+
+// DirectionsLeg is the part of a multi-stop route between two consecutive waypoints.
+type DirectionsLeg struct {
+	From Waypoint
+	To   Waypoint
+	// Origin and Destination are the places the API resolved the waypoints to.
+	Origin      Place
+	Destination Place
+	// Route is the route of the leg. Its StepIndexes refer to the Steps of the combined response.
+	Route Route
+	// StepStart and StepEnd are the boundaries of the leg in the Steps of the combined response,
+	// the steps of the leg having the indexes StepStart to StepEnd-1.
+	StepStart int
+	StepEnd   int
+}
+
+// DirectionsVia returns the directions of a route visiting the given waypoints in order, by requesting the
+// directions between every pair of consecutive waypoints and combining them into a single response.
+// Only the first route of every leg is used, so alternate routes are not part of the response.
+func DirectionsVia(ctx context.Context, c Client, waypoints []Waypoint, opts ...RequestOption) (*MultiStopDirectionsResponse, error) {
+	if len(waypoints) < 2 {
+		return nil, errors.New("at least two waypoints are required")
+	}
+
+	for i, w := range waypoints {
+		if err := w.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid waypoint %d: %w", i, err)
+		}
+	}
+
+	res := &MultiStopDirectionsResponse{Legs: make([]DirectionsLeg, 0, len(waypoints)-1)}
+	var route Route
+	var names []string
+	for i := 1; i < len(waypoints); i++ {
+		from, to := waypoints[i-1], waypoints[i]
+		directions, err := c.DirectionsBetween(ctx, from, to, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("directions of leg %d: %w", i-1, err)
+		}
+		if len(directions.Routes) == 0 {
+			return nil, fmt.Errorf("directions of leg %d: no route from %q to %q", i-1, from, to)
+		}
+
+		leg := DirectionsLeg{
+			From:        from,
+			To:          to,
+			Origin:      directions.Origin,
+			Destination: directions.Destination,
+			Route:       directions.Routes[0],
+			StepStart:   len(res.Steps),
+		}
+		leg.Route.StepIndexes, err = res.appendSteps(directions)
+		if err != nil {
+			return nil, fmt.Errorf("directions of leg %d: %w", i-1, err)
+		}
+		leg.StepEnd = len(res.Steps)
+		res.Legs = append(res.Legs, leg)
+
+		route.StepIndexes = append(route.StepIndexes, leg.Route.StepIndexes...)
+		route.DistanceMeters += leg.Route.DistanceMeters
+		route.DurationSeconds += leg.Route.DurationSeconds
+		route.HasTolls = route.HasTolls || leg.Route.HasTolls
+		if route.TransportType == "" {
+			route.TransportType = leg.Route.TransportType
+		}
+		if leg.Route.Name != "" {
+			names = append(names, leg.Route.Name)
+		}
+	}
+
+	route.Name = strings.Join(names, ", ")
+	res.Origin = res.Legs[0].Origin
+	res.Destination = res.Legs[len(res.Legs)-1].Destination
+	res.Routes = []Route{route}
+	return res, nil
+}
+
+// appendSteps appends the steps of the first route of the directions of a leg and their step paths,
+// and returns the indexes of the appended steps.
+func (r *MultiStopDirectionsResponse) appendSteps(directions *DirectionsResponse) ([]int, error) {
+	route, err := directions.resolveRoute(0)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]int, 0, len(route.Steps))
+	paths := make(map[int]int)
+	for _, step := range route.Steps {
+		path, ok := paths[step.StepPathIndex]
+		if !ok {
+			path = len(r.StepPaths)
+			paths[step.StepPathIndex] = path
+			r.StepPaths = append(r.StepPaths, step.Path)
+		}
+		step.StepPathIndex = path
+		indexes = append(indexes, len(r.Steps))
+		r.Steps = append(r.Steps, step.Step)
+	}
+	return indexes, nil
+}
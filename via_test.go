@@ -0,0 +1,104 @@
+package applemaps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newDirectionsTestServer returns a server answering Directions requests with two routes, the first of which
+// consists of the first and the last of three steps. Requests with the destination "invalid" fail.
+func newDirectionsTestServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/directions", func(w http.ResponseWriter, r *http.Request) {
+		origin, destination := r.URL.Query().Get("origin"), r.URL.Query().Get("destination")
+		if destination == "invalid" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(badRequest_ErrorResponse))
+			return
+		}
+		name := origin + " to " + destination
+		res := DirectionsResponse{
+			Origin:      Place{Name: origin},
+			Destination: Place{Name: destination},
+			Routes: []Route{
+				{Name: name, DistanceMeters: 100, DurationSeconds: 10, StepIndexes: []int{0, 2}, TransportType: "Automobile"},
+				{Name: "alternate", DistanceMeters: 200, DurationSeconds: 20, StepIndexes: []int{1}, TransportType: "Automobile"},
+			},
+			Steps: []Step{
+				{Instructions: name + " first", StepPathIndex: 0},
+				{Instructions: "alternate", StepPathIndex: 1},
+				{Instructions: name + " last", StepPathIndex: 2},
+			},
+			StepPaths: [][]Location{{NewLocation(1, 1)}, {NewLocation(2, 2)}, {NewLocation(3, 3)}},
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestDirectionsVia(t *testing.T) {
+	testServer := newDirectionsTestServer()
+	defer testServer.Close()
+
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL))
+	waypoints := []Waypoint{
+		NewAddressWaypoint("A"),
+		NewLocationWaypoint(NewLocation(37.7857, -122.4011)),
+		NewAddressWaypoint("C"),
+	}
+	res, err := DirectionsVia(context.Background(), mapsClient, waypoints)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "A", res.Origin.Name)
+	assert.Equal(t, "C", res.Destination.Name)
+	assert.Len(t, res.Routes, 1)
+	assert.Equal(t, Route{
+		Name:            "A to 37.7857,-122.4011, 37.7857,-122.4011 to C",
+		DistanceMeters:  200,
+		DurationSeconds: 20,
+		StepIndexes:     []int{0, 1, 2, 3},
+		TransportType:   "Automobile",
+	}, res.Routes[0])
+	assert.Len(t, res.Steps, 4)
+	assert.Len(t, res.StepPaths, 4)
+	for i, step := range res.Steps {
+		assert.Equal(t, i, step.StepPathIndex)
+		assert.NotEqual(t, "alternate", step.Instructions)
+	}
+	assert.Equal(t, []Location{NewLocation(3, 3)}, res.StepPaths[3])
+
+	assert.Len(t, res.Legs, 2)
+	leg := res.Legs[1]
+	assert.Equal(t, waypoints[1], leg.From)
+	assert.Equal(t, waypoints[2], leg.To)
+	assert.Equal(t, "C", leg.Destination.Name)
+	assert.Equal(t, []int{2, 3}, leg.Route.StepIndexes)
+	assert.Equal(t, 2, leg.StepStart)
+	assert.Equal(t, 4, leg.StepEnd)
+	assert.Equal(t, "37.7857,-122.4011 to C last", res.Steps[leg.StepEnd-1].Instructions)
+}
+
+func TestDirectionsVia_Errors(t *testing.T) {
+	testServer := newDirectionsTestServer()
+	defer testServer.Close()
+
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL))
+	_, err := DirectionsVia(context.Background(), mapsClient, []Waypoint{NewAddressWaypoint("A")})
+	assert.Error(t, err)
+
+	waypoints := []Waypoint{NewAddressWaypoint("A"), NewAddressWaypoint("B"), NewAddressWaypoint("invalid")}
+	_, err = DirectionsVia(context.Background(), mapsClient, waypoints)
+	assert.True(t, errors.Is(err, ErrBadRequest))
+	assert.Contains(t, err.Error(), "leg 1")
+}
@@ -0,0 +1,78 @@
+package applemaps
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Waypoint is the origin or destination of a route, given as coordinates, an address or a place ID.
+// Use NewWaypoint to create a waypoint from the strings accepted by the directions endpoint.
+type Waypoint struct {
+	address  string
+	location *Location
+	placeID  string
+}
+
+// NewWaypoint creates a waypoint from a string in the format of the origin and destination parameters
+// of the directions endpoint, which is either coordinates in the format "latitude,longitude",
+// for example "37.7857,-122.4011", or an address.
+func NewWaypoint(s string) Waypoint {
+	if parts := strings.Split(s, ","); len(parts) == 2 {
+		lat, latErr := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+		lng, lngErr := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if latErr == nil && lngErr == nil {
+			return NewLocationWaypoint(NewLocation(lat, lng))
+		}
+	}
+	return NewAddressWaypoint(s)
+}
+
+// NewAddressWaypoint creates a waypoint from an address, which the API geocodes.
+func NewAddressWaypoint(address string) Waypoint {
+	return Waypoint{address: address}
+}
+
+// NewLocationWaypoint creates a waypoint from coordinates.
+func NewLocationWaypoint(location Location) Waypoint {
+	return Waypoint{location: &location}
+}
+
+// NewPlaceWaypoint creates a waypoint from a place ID, for example the ID of a Place returned by Search.
+// Since the directions endpoint does not accept place IDs, the coordinates of the place are looked up
+// before requesting directions.
+func NewPlaceWaypoint(id string) Waypoint {
+	return Waypoint{placeID: id}
+}
+
+// String returns the coordinates of the waypoint in the format "latitude,longitude", its address or its place ID.
+func (w Waypoint) String() string {
+	switch {
+	case w.location != nil:
+		return w.location.String()
+	case w.placeID != "":
+		return w.placeID
+	default:
+		return w.address
+	}
+}
+
+// Validate returns an error if the waypoint is empty or its coordinates are out of range.
+func (w Waypoint) Validate() error {
+	switch {
+	case w.location != nil:
+		return w.location.Validate()
+	case w.placeID == "" && strings.TrimSpace(w.address) == "":
+		return errors.New("waypoint cannot be empty")
+	}
+	return nil
+}
+
+// cacheValue returns the value identifying the waypoint in cache keys, which is the origin or destination parameter
+// sent for the waypoint. Place IDs are only resolved to coordinates when the request is sent, so they are prefixed instead.
+func (w Waypoint) cacheValue() string {
+	if w.location == nil && w.placeID != "" {
+		return "place:" + w.placeID
+	}
+	return w.String()
+}
@@ -0,0 +1,81 @@
+package applemaps
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWaypoint(t *testing.T) {
+	assert.Equal(t, NewLocationWaypoint(NewLocation(37.7857, -122.4011)), NewWaypoint("37.7857,-122.4011"))
+	assert.Equal(t, NewLocationWaypoint(NewLocation(37.7857, -122.4011)), NewWaypoint("37.7857, -122.4011"))
+	assert.Equal(t, NewAddressWaypoint("Prager Straße 15, Dresden"), NewWaypoint("Prager Straße 15, Dresden"))
+	assert.Equal(t, NewAddressWaypoint("Dresden"), NewWaypoint("Dresden"))
+}
+
+func TestWaypoint_String(t *testing.T) {
+	assert.Equal(t, "37.7857,-122.4011", NewLocationWaypoint(NewLocation(37.7857, -122.4011)).String())
+	assert.Equal(t, "Dresden", NewAddressWaypoint("Dresden").String())
+	assert.Equal(t, "I7C250D2CDCB364A", NewPlaceWaypoint("I7C250D2CDCB364A").String())
+}
+
+func TestWaypoint_Validate(t *testing.T) {
+	assert.NoError(t, NewAddressWaypoint("Dresden").Validate())
+	assert.NoError(t, NewPlaceWaypoint("I7C250D2CDCB364A").Validate())
+	assert.NoError(t, NewLocationWaypoint(NewLocation(-90, 180)).Validate())
+
+	assert.Error(t, Waypoint{}.Validate())
+	assert.Error(t, NewAddressWaypoint("  ").Validate())
+	assert.Error(t, NewPlaceWaypoint("").Validate())
+	assert.Error(t, NewLocationWaypoint(NewLocation(91, 0)).Validate())
+	assert.Error(t, NewLocationWaypoint(NewLocation(0, -180.5)).Validate())
+	assert.Error(t, NewLocationWaypoint(NewLocation(math.NaN(), 0)).Validate())
+}
+
+func TestWaypoint_CacheValue(t *testing.T) {
+	assert.Equal(t, "Dresden", NewAddressWaypoint("Dresden").cacheValue())
+	assert.Equal(t, "51.0453064,13.7359337", NewLocationWaypoint(NewLocation(51.0453064, 13.7359337)).cacheValue())
+	assert.Equal(t, "place:I7C250D2CDCB364A", NewPlaceWaypoint("I7C250D2CDCB364A").cacheValue())
+	assert.NotEqual(t, NewAddressWaypoint("I7C250D2CDCB364A").cacheValue(), NewPlaceWaypoint("I7C250D2CDCB364A").cacheValue())
+}
+
+func TestDirections_Waypoints(t *testing.T) {
+	var requests int32
+	var query url.Values
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(accessToken_SuccessResponse))
+	})
+	mux.HandleFunc("/place/I7C250D2CDCB364A", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(place_SuccessResponse))
+	})
+	mux.HandleFunc("/directions", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		query = r.URL.Query()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(directions_SuccessResponse))
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+	mapsClient := NewAppleMaps(testServer.Client(), "jwt", WithCustomURL(testServer.URL))
+
+	_, err := mapsClient.DirectionsBetween(context.Background(), NewLocationWaypoint(NewLocation(51.0453064, 13.7359337)), NewPlaceWaypoint("I7C250D2CDCB364A"))
+	assert.NoError(t, err)
+	assert.Equal(t, "51.0453064,13.7359337", query.Get("origin"))
+	assert.Equal(t, "51.0658585,13.7466163", query.Get("destination"))
+
+	_, err = mapsClient.DirectionsBetween(context.Background(), NewPlaceWaypoint("unknown"), NewAddressWaypoint("Dresden"))
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = mapsClient.DirectionsBetween(context.Background(), NewAddressWaypoint("Dresden"), NewLocationWaypoint(NewLocation(100, 0)))
+	assert.ErrorContains(t, err, "invalid destination")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}